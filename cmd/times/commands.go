@@ -0,0 +1,161 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goccha/times/pkg/civil"
+	"github.com/goccha/times/pkg/gauge"
+	"github.com/goccha/times/pkg/weeks"
+)
+
+// layouts 日時の引数として受け付ける書式(タイムゾーンの指定が無い場合は -tz で解釈する)
+var layouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+func parseTime(s string, loc *time.Location) (time.Time, error) {
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date-time %q", s)
+}
+
+// parseGauge 開始日時・終了日時、または ISO 8601 形式の期間を解析する
+func parseGauge(args []string, loc *time.Location) (*gauge.TimeGauge, error) {
+	if len(args) == 1 {
+		return gauge.ParseInterval(args[0], gauge.InLocation(loc))
+	}
+	begin, err := parseTime(args[0], loc)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseTime(args[1], loc)
+	if err != nil {
+		return nil, err
+	}
+	return gauge.NewChecked(begin, end, gauge.AllowZero(), gauge.AllowMixedLocations())
+}
+
+func parseRule(s string) (weeks.Rule, error) {
+	switch strings.ToLower(s) {
+	case "sunday", "sun":
+		return weeks.Sunday, nil
+	case "iso", "monday", "mon":
+		return weeks.ISO, nil
+	}
+	return 0, fmt.Errorf("invalid week rule %q", s)
+}
+
+func durationCommand(_ *flag.FlagSet) func(o *options, args []string) ([]result, error) {
+	return func(o *options, args []string) ([]result, error) {
+		tg, err := parseGauge(args, o.loc)
+		if err != nil {
+			return nil, err
+		}
+		return []result{newGaugeResult("", tg, o.loc)}, nil
+	}
+}
+
+func splitCommand(fs *flag.FlagSet) func(o *options, args []string) ([]result, error) {
+	at := fs.String("at", "00:00", "基準時刻")
+	return func(o *options, args []string) ([]result, error) {
+		tod, err := civil.ParseTimeOfDay(*at)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errUsage, err)
+		}
+		tg, err := parseGauge(args, o.loc)
+		if err != nil {
+			return nil, err
+		}
+		segments, err := tg.SplitAt(tod, o.loc)
+		if err != nil {
+			return nil, err
+		}
+		results := make([]result, 0, len(segments))
+		for i := range segments {
+			results = append(results, newGaugeResult(segments[i].Date(), &segments[i], o.loc))
+		}
+		return results, nil
+	}
+}
+
+func formatCommand(fs *flag.FlagSet) func(o *options, args []string) ([]result, error) {
+	layout := fs.String("layout", "%h:%m", "書式(%v %h %m %s %S %M %n)")
+	return func(o *options, args []string) ([]result, error) {
+		tg, err := parseGauge(args, o.loc)
+		if err != nil {
+			return nil, err
+		}
+		n := strings.Count(*layout, "%") - 2*strings.Count(*layout, "%%")
+		values := make([]interface{}, n)
+		for i := range values {
+			values[i] = tg
+		}
+		return []result{&formatResult{
+			Begin: tg.Begin().In(o.loc),
+			End:   tg.End().In(o.loc),
+			Text:  fmt.Sprintf(*layout, values...),
+		}}, nil
+	}
+}
+
+func weekCommand(fs *flag.FlagSet) func(o *options, args []string) ([]result, error) {
+	rule := fs.String("rule", "sunday", "週の開始曜日(sunday, iso)")
+	return func(o *options, args []string) ([]result, error) {
+		r, err := parseRule(*rule)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errUsage, err)
+		}
+		t, err := parseTime(args[0], o.loc)
+		if err != nil {
+			return nil, err
+		}
+		var days []time.Time
+		var week int
+		if r == weeks.ISO {
+			days, week = weeks.ISOTimes(t), weeks.ISOWeekOfMonth(t)
+		} else {
+			days, week = weeks.Times(t), weeks.WeekOfMonth(t)
+		}
+		res := &weekResult{Date: civil.DateOf(t), WeekOfMonth: week, Dates: make([]civil.Date, 0, len(days))}
+		res.ISOYear, res.ISOWeek = t.ISOWeek()
+		for _, d := range days {
+			res.Dates = append(res.Dates, civil.DateOf(d))
+		}
+		return []result{res}, nil
+	}
+}
+
+func sameCommand(fs *flag.FlagSet) func(o *options, args []string) ([]result, error) {
+	rule := fs.String("rule", "sunday", "週の開始曜日(sunday, iso)")
+	return func(o *options, args []string) ([]result, error) {
+		r, err := parseRule(*rule)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errUsage, err)
+		}
+		t1, err := parseTime(args[0], o.loc)
+		if err != nil {
+			return nil, err
+		}
+		t2, err := parseTime(args[1], o.loc)
+		if err != nil {
+			return nil, err
+		}
+		same := weeks.Same(t1, t2)
+		if r == weeks.ISO {
+			same = weeks.ISOSame(t1, t2)
+		}
+		return []result{&sameResult{Date1: civil.DateOf(t1), Date2: civil.DateOf(t2), Same: same}}, nil
+	}
+}
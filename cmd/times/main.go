@@ -0,0 +1,173 @@
+// times は期間の計測・分割・書式化と週の計算をコマンドラインから行う
+//
+//	times [-json] [-tz NAME] <command> [flags] [args...]
+//
+// 引数を省略するか "-" を指定した場合は、標準入力の各行を引数として処理する。
+// 行はタブ・カンマがあればそれで区切り、無ければ空白で区切る。
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const usage = `usage: times [-json] [-tz NAME] <command> [flags] [args...]
+
+commands:
+  duration BEGIN END | INTERVAL                 期間を表示する
+  split    [-at 00:00] BEGIN END | INTERVAL     期間を基準時刻で分割する
+  format   [-layout %h:%m] BEGIN END | INTERVAL 期間を書式化する(%v %h %m %s %S %M %n)
+  week     [-rule sunday|iso] DATE              週の日付と月の何週目かを表示する
+  same     [-rule sunday|iso] DATE DATE         同じ週に含まれるかを表示する
+
+引数を省略するか "-" を指定した場合は、標準入力の各行を引数として処理する。
+`
+
+// errUsage 引数の誤り
+var errUsage = errors.New("invalid arguments")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// options 全コマンド共通のオプション
+type options struct {
+	json bool
+	tz   string
+	loc  *time.Location
+}
+
+func (o *options) register(fs *flag.FlagSet) {
+	fs.BoolVar(&o.json, "json", o.json, "JSON で出力する")
+	fs.StringVar(&o.tz, "tz", o.tz, "タイムゾーンの指定が無い日時を解釈・出力するタイムゾーン(既定は Local)")
+}
+
+// command サブコマンド(setup でフラグを登録し、実行する関数を返す)
+type command struct {
+	setup func(fs *flag.FlagSet) func(o *options, args []string) ([]result, error)
+	arity []int // 受け付ける引数の数
+}
+
+var commands = map[string]command{
+	"duration": {setup: durationCommand, arity: []int{1, 2}},
+	"split":    {setup: splitCommand, arity: []int{1, 2}},
+	"format":   {setup: formatCommand, arity: []int{1, 2}},
+	"week":     {setup: weekCommand, arity: []int{1}},
+	"same":     {setup: sameCommand, arity: []int{2}},
+}
+
+// run コマンドを実行し、終了コードを返す
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	o := &options{}
+	fs := flag.NewFlagSet("times", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() { _, _ = io.WriteString(stderr, usage) }
+	o.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	name := fs.Arg(0)
+	cmd, ok := commands[name]
+	if !ok {
+		_, _ = fmt.Fprintf(stderr, "times: unknown command %q\n", name)
+		fs.Usage()
+		return 2
+	}
+	sub := flag.NewFlagSet("times "+name, flag.ContinueOnError)
+	sub.SetOutput(stderr)
+	sub.Usage = fs.Usage
+	o.register(sub)
+	exec := cmd.setup(sub)
+	if err := sub.Parse(fs.Args()[1:]); err != nil {
+		return 2
+	}
+	loc, err := loadLocation(o.tz)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "times: %v\n", err)
+		return 2
+	}
+	o.loc = loc
+
+	var results []result
+	code := 0
+	if sub.NArg() == 0 || sub.NArg() == 1 && sub.Arg(0) == "-" {
+		s := bufio.NewScanner(stdin)
+		for line := 1; s.Scan(); line++ {
+			fields := split(s.Text())
+			if len(fields) == 0 {
+				continue
+			}
+			rs, err := call(cmd, exec, o, fields)
+			if err != nil {
+				_, _ = fmt.Fprintf(stderr, "times: line %d: %v\n", line, err)
+				code = 1
+				continue
+			}
+			results = append(results, rs...)
+		}
+		if err := s.Err(); err != nil {
+			_, _ = fmt.Fprintf(stderr, "times: %v\n", err)
+			return 1
+		}
+	} else {
+		rs, err := call(cmd, exec, o, sub.Args())
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "times: %v\n", err)
+			if errors.Is(err, errUsage) {
+				fs.Usage()
+				return 2
+			}
+			return 1
+		}
+		results = rs
+	}
+	if err := write(stdout, results, o.json); err != nil {
+		_, _ = fmt.Fprintf(stderr, "times: %v\n", err)
+		return 1
+	}
+	return code
+}
+
+// call 引数の数を検査してコマンドを実行する
+func call(cmd command, exec func(o *options, args []string) ([]result, error), o *options, args []string) ([]result, error) {
+	for _, n := range cmd.arity {
+		if len(args) == n {
+			return exec(o, args)
+		}
+	}
+	return nil, fmt.Errorf("%w: expected %v arguments, got %d", errUsage, cmd.arity, len(args))
+}
+
+// split 標準入力の1行を引数に分割する
+func split(line string) []string {
+	if !strings.ContainsAny(line, "\t,") {
+		return strings.Fields(line)
+	}
+	fields := strings.FieldsFunc(line, func(r rune) bool {
+		return r == '\t' || r == ','
+	})
+	args := fields[:0]
+	for _, f := range fields {
+		if f = strings.TrimSpace(f); f != "" {
+			args = append(args, f)
+		}
+	}
+	return args
+}
+
+func loadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(name)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func exec(t *testing.T, stdin string, args ...string) (string, string, int) {
+	t.Helper()
+	var stdout, stderr strings.Builder
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return stdout.String(), stderr.String(), code
+}
+
+func TestRun_Duration(t *testing.T) {
+	out, _, code := exec(t, "", "-tz", "Asia/Tokyo", "duration", "2020-04-01T22:00", "2020-04-02T06:15:12")
+	if code != 0 {
+		t.Fatalf("expected=0, actual=%d", code)
+	}
+	expected := "BEGIN                      END                        DURATION  HOURS\n" +
+		"2020-04-01T22:00:00+09:00  2020-04-02T06:15:12+09:00  8h15m12s  8.253333333333334\n"
+	if out != expected {
+		t.Errorf("expected=%q, actual=%q", expected, out)
+	}
+	// 秒を省略した RFC 3339 形式
+	out, _, code = exec(t, "", "-tz", "Asia/Tokyo", "duration", "2020-04-01T13:00Z", "2020-04-01T23:15+09:00")
+	if code != 0 {
+		t.Fatalf("expected=0, actual=%d", code)
+	}
+	expected = "BEGIN                      END                        DURATION  HOURS\n" +
+		"2020-04-01T22:00:00+09:00  2020-04-01T23:15:00+09:00  1h15m0s   1.25\n"
+	if out != expected {
+		t.Errorf("expected=%q, actual=%q", expected, out)
+	}
+}
+
+func TestRun_Split(t *testing.T) {
+	out, _, code := exec(t, "", "-json", "split", "-tz", "Asia/Tokyo", "-at", "05:00", "2020-04-01T22:00:00+09:00/PT10H")
+	if code != 0 {
+		t.Fatalf("expected=0, actual=%d", code)
+	}
+	var actual []gaugeResult
+	if err := json.Unmarshal([]byte(out), &actual); err != nil {
+		t.Fatal(err)
+	}
+	expected := []struct {
+		date  string
+		hours float64
+	}{
+		{"2020-04-02", 7},
+		{"2020-04-03", 3},
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("expected=%d, actual=%d", len(expected), len(actual))
+	}
+	for i, r := range actual {
+		if r.Date != expected[i].date || r.Hours != expected[i].hours {
+			t.Errorf("[%d] expected=%v, actual=%s %v", i, expected[i], r.Date, r.Hours)
+		}
+	}
+}
+
+func TestRun_Format(t *testing.T) {
+	out, _, code := exec(t, "", "-json", "format", "-layout", "%h時間%m分%s秒 (%%)", "2020-04-01T22:00:00+09:00", "2020-04-02T06:15:12+09:00")
+	if code != 0 {
+		t.Fatalf("expected=0, actual=%d", code)
+	}
+	var actual []formatResult
+	if err := json.Unmarshal([]byte(out), &actual); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "8時間15分12秒 (%)"; len(actual) != 1 || actual[0].Text != expected {
+		t.Errorf("expected=%s, actual=%v", expected, actual)
+	}
+}
+
+func TestRun_Batch(t *testing.T) {
+	stdin := "2020-04-01\n" +
+		"\n" +
+		"yesterday\n" +
+		"2020-04-30\n"
+	out, errOut, code := exec(t, stdin, "week", "-rule", "iso")
+	if code != 1 {
+		t.Errorf("expected=1, actual=%d", code)
+	}
+	expected := "DATE        ISO WEEK  WEEK  DATES\n" +
+		"2020-04-01  2020-W14  1     2020-03-30 2020-03-31 2020-04-01 2020-04-02 2020-04-03 2020-04-04 2020-04-05\n" +
+		"2020-04-30  2020-W18  5     2020-04-27 2020-04-28 2020-04-29 2020-04-30 2020-05-01 2020-05-02 2020-05-03\n"
+	if out != expected {
+		t.Errorf("expected=%q, actual=%q", expected, out)
+	}
+	if !strings.Contains(errOut, "line 3") {
+		t.Errorf("unexpected error output %q", errOut)
+	}
+
+	out, _, code = exec(t, "2020-04-04,2020-04-05\n2020-04-05\t2020-04-11\n", "-json", "same", "-")
+	if code != 0 {
+		t.Fatalf("expected=0, actual=%d", code)
+	}
+	var actual []sameResult
+	if err := json.Unmarshal([]byte(out), &actual); err != nil {
+		t.Fatal(err)
+	}
+	if len(actual) != 2 || actual[0].Same || !actual[1].Same {
+		t.Errorf("unexpected result %v", actual)
+	}
+}
+
+func TestRun_Week(t *testing.T) {
+	// 2021-01-01 は ISO 週番号で 2020 年の第53週
+	out, _, code := exec(t, "", "-json", "week", "2021-01-01")
+	if code != 0 {
+		t.Fatalf("expected=0, actual=%d", code)
+	}
+	var actual []weekResult
+	if err := json.Unmarshal([]byte(out), &actual); err != nil {
+		t.Fatal(err)
+	}
+	if len(actual) != 1 || actual[0].ISOYear != 2020 || actual[0].ISOWeek != 53 {
+		t.Errorf("expected=2020-W53, actual=%v", actual)
+	}
+}
+
+func TestRun_Error(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected int
+	}{
+		{nil, 2},
+		{[]string{"unknown"}, 2},
+		{[]string{"-tz", "Nowhere/City", "duration", "2020-04-01", "2020-04-02"}, 2},
+		{[]string{"same", "2020-04-01"}, 2},
+		{[]string{"split", "-at", "25:00", "2020-04-01", "2020-04-02"}, 2},
+		{[]string{"duration", "2020-04-02", "2020-04-01"}, 1},
+	}
+	for _, tt := range tests {
+		if _, _, code := exec(t, "", tt.args...); code != tt.expected {
+			t.Errorf("%v: expected=%d, actual=%d", tt.args, tt.expected, code)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/goccha/times/pkg/civil"
+	"github.com/goccha/times/pkg/gauge"
+)
+
+// result コマンドの結果の1行
+type result interface {
+	columns() []string
+	cells() []string
+}
+
+// write 結果を表形式または JSON で出力する
+func write(w io.Writer, results []result, asJSON bool) error {
+	if asJSON {
+		if results == nil {
+			results = []result{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+	if len(results) == 0 {
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = io.WriteString(tw, strings.Join(results[0].columns(), "\t")+"\n")
+	for _, r := range results {
+		_, _ = io.WriteString(tw, strings.Join(r.cells(), "\t")+"\n")
+	}
+	return tw.Flush()
+}
+
+// gaugeResult 期間
+type gaugeResult struct {
+	Date     string    `json:"date,omitempty"`
+	Begin    time.Time `json:"begin"`
+	End      time.Time `json:"end"`
+	Duration string    `json:"duration"`
+	Hours    float64   `json:"hours"`
+	Seconds  float64   `json:"seconds"`
+}
+
+func newGaugeResult(date string, tg *gauge.TimeGauge, loc *time.Location) *gaugeResult {
+	return &gaugeResult{
+		Date:     date,
+		Begin:    tg.Begin().In(loc),
+		End:      tg.End().In(loc),
+		Duration: tg.Duration().String(),
+		Hours:    tg.Hours(),
+		Seconds:  tg.Duration().Seconds(),
+	}
+}
+
+func (r *gaugeResult) columns() []string {
+	if r.Date != "" {
+		return []string{"DATE", "BEGIN", "END", "DURATION", "HOURS"}
+	}
+	return []string{"BEGIN", "END", "DURATION", "HOURS"}
+}
+
+func (r *gaugeResult) cells() []string {
+	cells := []string{
+		r.Begin.Format(time.RFC3339),
+		r.End.Format(time.RFC3339),
+		r.Duration,
+		strconv.FormatFloat(r.Hours, 'f', -1, 64),
+	}
+	if r.Date != "" {
+		return append([]string{r.Date}, cells...)
+	}
+	return cells
+}
+
+// formatResult 書式化した期間
+type formatResult struct {
+	Begin time.Time `json:"begin"`
+	End   time.Time `json:"end"`
+	Text  string    `json:"text"`
+}
+
+func (r *formatResult) columns() []string {
+	return []string{"BEGIN", "END", "TEXT"}
+}
+
+func (r *formatResult) cells() []string {
+	return []string{r.Begin.Format(time.RFC3339), r.End.Format(time.RFC3339), r.Text}
+}
+
+// weekResult 週の日付
+type weekResult struct {
+	Date        civil.Date   `json:"date"`
+	ISOYear     int          `json:"isoYear"`
+	ISOWeek     int          `json:"isoWeek"`
+	WeekOfMonth int          `json:"weekOfMonth"`
+	Dates       []civil.Date `json:"dates"`
+}
+
+func (r *weekResult) columns() []string {
+	return []string{"DATE", "ISO WEEK", "WEEK", "DATES"}
+}
+
+func (r *weekResult) cells() []string {
+	dates := make([]string, len(r.Dates))
+	for i, d := range r.Dates {
+		dates[i] = d.String()
+	}
+	return []string{r.Date.String(), fmt.Sprintf("%d-W%02d", r.ISOYear, r.ISOWeek), strconv.Itoa(r.WeekOfMonth), strings.Join(dates, " ")}
+}
+
+// sameResult 同じ週に含まれるか
+type sameResult struct {
+	Date1 civil.Date `json:"date1"`
+	Date2 civil.Date `json:"date2"`
+	Same  bool       `json:"same"`
+}
+
+func (r *sameResult) columns() []string {
+	return []string{"DATE1", "DATE2", "SAME"}
+}
+
+func (r *sameResult) cells() []string {
+	return []string{r.Date1.String(), r.Date2.String(), strconv.FormatBool(r.Same)}
+}
@@ -0,0 +1,191 @@
+package civil
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/goccha/times/pkg/zone"
+)
+
+// DateLayout Date の文字列表現の書式
+const DateLayout = time.DateOnly
+
+const secondsPerDay = 24 * 60 * 60
+
+// Date タイムゾーン・時刻を持たない日付
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// DateOf 日時の壁時計の日付を返す
+func DateOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{Year: y, Month: m, Day: d}
+}
+
+// Today 指定したロケーションの今日の日付を返す
+func Today(loc *time.Location) Date {
+	return DateOf(time.Now().In(loc))
+}
+
+// ParseDate "2006-01-02" 形式の日付を解析する
+func ParseDate(s string) (Date, error) {
+	return ParseDateLayout(DateLayout, s)
+}
+
+// ParseDateLayout 指定した書式の日付を解析する(時刻・タイムゾーンは無視する)
+func ParseDateLayout(layout, s string) (Date, error) {
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("civil: invalid date %q: %w", s, err)
+	}
+	return DateOf(t), nil
+}
+
+// String "2006-01-02" 形式の文字列を返す
+func (d Date) String() string {
+	return d.Format(DateLayout)
+}
+
+// Format 指定した書式の文字列を返す
+func (d Date) Format(layout string) string {
+	return d.time().Format(layout)
+}
+
+// IsZero ゼロ値かどうか
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// IsValid 存在する日付かどうか
+func (d Date) IsValid() bool {
+	return DateOf(d.time()) == d
+}
+
+// Weekday 曜日を返す
+func (d Date) Weekday() time.Weekday {
+	return d.time().Weekday()
+}
+
+// YearDay 年の何日目かを返す
+func (d Date) YearDay() int {
+	return d.time().YearDay()
+}
+
+// AddDays 日数を加算した日付を返す
+func (d Date) AddDays(n int) Date {
+	return DateOf(d.time().AddDate(0, 0, n))
+}
+
+// AddDate 年・月・日を加算した日付を返す(time.Time.AddDate と同様に正規化する)
+func (d Date) AddDate(years, months, days int) Date {
+	return DateOf(d.time().AddDate(years, months, days))
+}
+
+// DaysSince u から d までの日数を返す
+func (d Date) DaysSince(u Date) int {
+	// time.Duration は約292年で飽和するため、UTC の0時の通算日で計算する
+	return int(d.time().Unix()/secondsPerDay - u.time().Unix()/secondsPerDay)
+}
+
+// Before d が u より前かどうか
+func (d Date) Before(u Date) bool {
+	return d.Compare(u) < 0
+}
+
+// After d が u より後かどうか
+func (d Date) After(u Date) bool {
+	return d.Compare(u) > 0
+}
+
+// Compare d が u より前の場合は -1、後の場合は +1、同じ場合は 0 を返す
+func (d Date) Compare(u Date) int {
+	switch {
+	case d.Year != u.Year:
+		return compareInt(d.Year, u.Year)
+	case d.Month != u.Month:
+		return compareInt(int(d.Month), int(u.Month))
+	}
+	return compareInt(d.Day, u.Day)
+}
+
+// In 指定したロケーションの0時の日時を返す(0時が存在しない場合は切り替え後の時刻)
+func (d Date) In(loc *time.Location) time.Time {
+	return d.At(0, 0, 0, 0, loc)
+}
+
+// At 指定したロケーション・時刻の日時を返す(夏時間の切り替えは zone.Compatible で解釈する)
+func (d Date) At(hour, min, sec, nsec int, loc *time.Location) time.Time {
+	t, _ := zone.Date(d.Year, d.Month, d.Day, hour, min, sec, nsec, loc, zone.Compatible)
+	return t
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+//
+// ゼロ値は空文字列に変換する。
+func (d Date) MarshalText() ([]byte, error) {
+	if d.IsZero() {
+		return []byte{}, nil
+	}
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+//
+// 空文字列はゼロ値とする。
+func (d *Date) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*d = Date{}
+		return nil
+	}
+	v, err := ParseDate(string(data))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+//
+// ゼロ値は NULL に変換する。
+func (d Date) Value() (driver.Value, error) {
+	if d.IsZero() {
+		return nil, nil
+	}
+	return d.String(), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (d *Date) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*d = Date{}
+		return nil
+	case time.Time:
+		*d = DateOf(v)
+		return nil
+	case string:
+		return d.UnmarshalText([]byte(v))
+	case []byte:
+		return d.UnmarshalText(v)
+	}
+	return fmt.Errorf("civil: cannot scan %T into Date", src)
+}
+
+func (d Date) time() time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, time.UTC)
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
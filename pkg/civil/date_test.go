@@ -0,0 +1,150 @@
+package civil
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDateOf(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	tm := time.Date(2020, 4, 1, 23, 0, 0, 0, ny)
+	if actual := DateOf(tm); actual != (Date{2020, time.April, 1}) {
+		t.Errorf("expected=2020-04-01, actual=%s", actual)
+	}
+	if actual := DateOf(tm.In(time.UTC)); actual != (Date{2020, time.April, 2}) {
+		t.Errorf("expected=2020-04-02, actual=%s", actual)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	d, err := ParseDate("2020-02-29")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.String() != "2020-02-29" || !d.IsValid() {
+		t.Errorf("expected=2020-02-29, actual=%s", d)
+	}
+	if d, err = ParseDateLayout("2006年1月2日", "2020年4月1日"); err != nil || d != (Date{2020, time.April, 1}) {
+		t.Errorf("expected=2020-04-01, actual=%s %v", d, err)
+	}
+	for _, s := range []string{"2019-02-29", "2020-4-1", "", "2020-04-01T00:00:00Z"} {
+		if _, err := ParseDate(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+	if (Date{2019, time.February, 29}).IsValid() {
+		t.Error("expected=false, actual=true")
+	}
+}
+
+func TestDate_Arithmetic(t *testing.T) {
+	d := Date{2020, time.January, 31}
+	if actual := d.AddDays(30); actual != (Date{2020, time.March, 1}) {
+		t.Errorf("expected=2020-03-01, actual=%s", actual)
+	}
+	if actual := d.AddDate(0, 1, 0); actual != (Date{2020, time.March, 2}) {
+		t.Errorf("expected=2020-03-02, actual=%s", actual)
+	}
+	if actual := (Date{2021, time.January, 1}).DaysSince(Date{2020, time.January, 1}); actual != 366 {
+		t.Errorf("expected=366, actual=%d", actual)
+	}
+	if actual := (Date{2020, time.January, 1}).DaysSince(Date{2020, time.January, 8}); actual != -7 {
+		t.Errorf("expected=-7, actual=%d", actual)
+	}
+	if actual := (Date{2500, time.January, 1}).DaysSince(Date{2000, time.January, 1}); actual != 182622 {
+		t.Errorf("expected=182622, actual=%d", actual)
+	}
+	if actual := (Date{1000, time.January, 1}).DaysSince(Date{2000, time.January, 1}); actual != -365242 {
+		t.Errorf("expected=-365242, actual=%d", actual)
+	}
+	if actual := d.Weekday(); actual != time.Friday {
+		t.Errorf("expected=Friday, actual=%s", actual)
+	}
+	if actual := (Date{2020, time.December, 31}).YearDay(); actual != 366 {
+		t.Errorf("expected=366, actual=%d", actual)
+	}
+}
+
+func TestDate_Compare(t *testing.T) {
+	a := Date{2020, time.April, 1}
+	b := Date{2020, time.April, 2}
+	if !a.Before(b) || a.After(b) || a.Compare(b) != -1 || b.Compare(a) != 1 || a.Compare(a) != 0 {
+		t.Errorf("unexpected comparison %s %s", a, b)
+	}
+	if !(Date{2019, time.December, 31}).Before(Date{2020, time.January, 1}) {
+		t.Error("expected=true, actual=false")
+	}
+}
+
+func TestDate_In(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	d := Date{2020, time.March, 8}
+	if actual := d.In(ny).Format(time.RFC3339); actual != "2020-03-08T00:00:00-05:00" {
+		t.Errorf("expected=2020-03-08T00:00:00-05:00, actual=%s", actual)
+	}
+	if actual := d.At(2, 30, 0, 0, ny).Format(time.RFC3339); actual != "2020-03-08T03:30:00-04:00" {
+		t.Errorf("expected=2020-03-08T03:30:00-04:00, actual=%s", actual)
+	}
+}
+
+func TestDate_JSON(t *testing.T) {
+	v := struct {
+		Date  Date  `json:"date"`
+		Empty *Date `json:"empty"`
+	}{Date: Date{2020, time.April, 1}}
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"date":"2020-04-01","empty":null}` {
+		t.Errorf("unexpected json %s", b)
+	}
+	v.Date = Date{}
+	if err = json.Unmarshal(b, &v); err != nil || v.Date != (Date{2020, time.April, 1}) {
+		t.Errorf("expected=2020-04-01, actual=%s %v", v.Date, err)
+	}
+	if err = json.Unmarshal([]byte(`{"date":"2020-13-01"}`), &v); err == nil {
+		t.Error("expected error")
+	}
+	// ゼロ値は空文字列で往復する
+	v.Date = Date{}
+	if b, err = json.Marshal(v); err != nil || string(b) != `{"date":"","empty":null}` {
+		t.Errorf("unexpected json %s %v", b, err)
+	}
+	v.Date = Date{2020, time.April, 1}
+	if err = json.Unmarshal(b, &v); err != nil || !v.Date.IsZero() {
+		t.Errorf("expected zero, actual=%s %v", v.Date, err)
+	}
+}
+
+func TestDate_SQL(t *testing.T) {
+	d := Date{2020, time.April, 1}
+	if v, _ := d.Value(); v != "2020-04-01" {
+		t.Errorf("expected=2020-04-01, actual=%v", v)
+	}
+	for _, src := range []interface{}{
+		"2020-04-01",
+		[]byte("2020-04-01"),
+		time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		var actual Date
+		if err := actual.Scan(src); err != nil || actual != d {
+			t.Errorf("%v: expected=%s, actual=%s %v", src, d, actual, err)
+		}
+	}
+	if err := d.Scan(nil); err != nil || !d.IsZero() {
+		t.Errorf("expected zero, actual=%s %v", d, err)
+	}
+	// ゼロ値は NULL で往復する
+	if v, err := d.Value(); err != nil || v != nil {
+		t.Errorf("expected=<nil>, actual=%v %v", v, err)
+	}
+	d = Date{2020, time.April, 1}
+	if err := d.Scan(""); err != nil || !d.IsZero() {
+		t.Errorf("expected zero, actual=%s %v", d, err)
+	}
+	if err := d.Scan(1); err == nil {
+		t.Error("expected error")
+	}
+}
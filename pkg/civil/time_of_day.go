@@ -0,0 +1,187 @@
+package civil
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+// TimeOfDay 日付・タイムゾーンを持たない時刻
+type TimeOfDay struct {
+	Hour       int
+	Minute     int
+	Second     int
+	Nanosecond int
+}
+
+// NewTimeOfDay 時刻を生成する(範囲外の値はエラー)
+func NewTimeOfDay(hour, min, sec, nsec int) (TimeOfDay, error) {
+	t := TimeOfDay{Hour: hour, Minute: min, Second: sec, Nanosecond: nsec}
+	if !t.IsValid() {
+		return TimeOfDay{}, fmt.Errorf("civil: invalid time of day %s", t)
+	}
+	return t, nil
+}
+
+// TimeOfDayOf 日時の壁時計の時刻を返す
+func TimeOfDayOf(t time.Time) TimeOfDay {
+	return TimeOfDay{Hour: t.Hour(), Minute: t.Minute(), Second: t.Second(), Nanosecond: t.Nanosecond()}
+}
+
+// ParseTimeOfDay "18:00", "18:00:00", "18:00:00.5" 形式の時刻を解析する
+func ParseTimeOfDay(s string) (TimeOfDay, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return TimeOfDay{}, fmt.Errorf("civil: invalid time of day %q", s)
+	}
+	var t TimeOfDay
+	var err error
+	if t.Hour, err = parseDigits(parts[0]); err != nil {
+		return TimeOfDay{}, fmt.Errorf("civil: invalid time of day %q", s)
+	}
+	if t.Minute, err = parseDigits(parts[1]); err != nil {
+		return TimeOfDay{}, fmt.Errorf("civil: invalid time of day %q", s)
+	}
+	if len(parts) == 3 {
+		sec, frac := parts[2], ""
+		if i := strings.IndexByte(sec, '.'); i >= 0 {
+			sec, frac = sec[:i], sec[i+1:]
+			if frac == "" || len(frac) > 9 {
+				return TimeOfDay{}, fmt.Errorf("civil: invalid time of day %q", s)
+			}
+		}
+		if t.Second, err = parseDigits(sec); err != nil {
+			return TimeOfDay{}, fmt.Errorf("civil: invalid time of day %q", s)
+		}
+		if frac != "" {
+			ns, err := strconv.Atoi(frac + strings.Repeat("0", 9-len(frac)))
+			if err != nil || ns < 0 {
+				return TimeOfDay{}, fmt.Errorf("civil: invalid time of day %q", s)
+			}
+			t.Nanosecond = ns
+		}
+	}
+	if !t.IsValid() {
+		return TimeOfDay{}, fmt.Errorf("civil: invalid time of day %q", s)
+	}
+	return t, nil
+}
+
+// parseDigits 2桁の数字を解析する
+func parseDigits(s string) (int, error) {
+	if len(s) != 2 || s[0] < '0' || s[0] > '9' || s[1] < '0' || s[1] > '9' {
+		return 0, fmt.Errorf("invalid digits %q", s)
+	}
+	return int(s[0]-'0')*10 + int(s[1]-'0'), nil
+}
+
+// String "15:04:05" 形式(ナノ秒がある場合は小数部を付加)の文字列を返す
+func (t TimeOfDay) String() string {
+	s := fmt.Sprintf("%02d:%02d:%02d", t.Hour, t.Minute, t.Second)
+	if t.Nanosecond == 0 {
+		return s
+	}
+	return s + strings.TrimRight(fmt.Sprintf(".%09d", t.Nanosecond), "0")
+}
+
+// IsValid 各値が範囲内かどうか
+func (t TimeOfDay) IsValid() bool {
+	return t.Hour >= 0 && t.Hour < 24 &&
+		t.Minute >= 0 && t.Minute < 60 &&
+		t.Second >= 0 && t.Second < 60 &&
+		t.Nanosecond >= 0 && t.Nanosecond < int(time.Second)
+}
+
+// Duration 0時からの経過時間を返す
+func (t TimeOfDay) Duration() time.Duration {
+	return time.Duration(t.Hour)*time.Hour + time.Duration(t.Minute)*time.Minute +
+		time.Duration(t.Second)*time.Second + time.Duration(t.Nanosecond)
+}
+
+// Add 時間を加算した時刻を返す(0時をまたぐ場合は折り返す)
+func (t TimeOfDay) Add(d time.Duration) TimeOfDay {
+	v := (t.Duration() + d%day) % day
+	if v < 0 {
+		v += day
+	}
+	return TimeOfDay{
+		Hour:       int(v / time.Hour),
+		Minute:     int(v % time.Hour / time.Minute),
+		Second:     int(v % time.Minute / time.Second),
+		Nanosecond: int(v % time.Second),
+	}
+}
+
+// Sub u から t までの時間を返す(t が u より前の場合は負)
+func (t TimeOfDay) Sub(u TimeOfDay) time.Duration {
+	return t.Duration() - u.Duration()
+}
+
+// Before t が u より前かどうか
+func (t TimeOfDay) Before(u TimeOfDay) bool {
+	return t.Compare(u) < 0
+}
+
+// After t が u より後かどうか
+func (t TimeOfDay) After(u TimeOfDay) bool {
+	return t.Compare(u) > 0
+}
+
+// Compare t が u より前の場合は -1、後の場合は +1、同じ場合は 0 を返す
+func (t TimeOfDay) Compare(u TimeOfDay) int {
+	switch {
+	case t.Hour != u.Hour:
+		return compareInt(t.Hour, u.Hour)
+	case t.Minute != u.Minute:
+		return compareInt(t.Minute, u.Minute)
+	case t.Second != u.Second:
+		return compareInt(t.Second, u.Second)
+	}
+	return compareInt(t.Nanosecond, u.Nanosecond)
+}
+
+// On 指定した日付・ロケーションの日時を返す(夏時間の切り替えは zone.Compatible で解釈する)
+func (t TimeOfDay) On(d Date, loc *time.Location) time.Time {
+	return d.At(t.Hour, t.Minute, t.Second, t.Nanosecond, loc)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (t TimeOfDay) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (t *TimeOfDay) UnmarshalText(data []byte) error {
+	v, err := ParseTimeOfDay(string(data))
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (t TimeOfDay) Value() (driver.Value, error) {
+	return t.String(), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (t *TimeOfDay) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*t = TimeOfDay{}
+		return nil
+	case time.Time:
+		*t = TimeOfDayOf(v)
+		return nil
+	case string:
+		return t.UnmarshalText([]byte(v))
+	case []byte:
+		return t.UnmarshalText(v)
+	}
+	return fmt.Errorf("civil: cannot scan %T into TimeOfDay", src)
+}
@@ -0,0 +1,108 @@
+package civil
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseTimeOfDay(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected TimeOfDay
+		str      string
+	}{
+		{"18:00", TimeOfDay{Hour: 18}, "18:00:00"},
+		{"18:00:30", TimeOfDay{Hour: 18, Second: 30}, "18:00:30"},
+		{"18:00:00.5", TimeOfDay{Hour: 18, Nanosecond: 500000000}, "18:00:00.5"},
+		{"00:00:00.000000001", TimeOfDay{Nanosecond: 1}, "00:00:00.000000001"},
+		{"23:59:59", TimeOfDay{23, 59, 59, 0}, "23:59:59"},
+	}
+	for _, tt := range tests {
+		actual, err := ParseTimeOfDay(tt.s)
+		if err != nil {
+			t.Errorf("%s: %v", tt.s, err)
+			continue
+		}
+		if actual != tt.expected {
+			t.Errorf("expected=%v, actual=%v", tt.expected, actual)
+		}
+		if actual.String() != tt.str {
+			t.Errorf("expected=%s, actual=%s", tt.str, actual)
+		}
+	}
+	for _, s := range []string{"", "18", "8:00", "24:00", "25:61", "18:60", "18:00:60", "18:00:00.", "18:00:00.1234567890", "18:00:00:00", "ab:cd"} {
+		if _, err := ParseTimeOfDay(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
+
+func TestNewTimeOfDay(t *testing.T) {
+	if _, err := NewTimeOfDay(25, 61, 0, 0); err == nil {
+		t.Error("expected error")
+	}
+	if v, err := NewTimeOfDay(9, 30, 0, 0); err != nil || v != (TimeOfDay{Hour: 9, Minute: 30}) {
+		t.Errorf("expected=09:30:00, actual=%s %v", v, err)
+	}
+}
+
+func TestTimeOfDay_Add(t *testing.T) {
+	tod := TimeOfDay{Hour: 22}
+	if actual := tod.Add(3 * time.Hour); actual != (TimeOfDay{Hour: 1}) {
+		t.Errorf("expected=01:00:00, actual=%s", actual)
+	}
+	if actual := tod.Add(-23 * time.Hour); actual != (TimeOfDay{Hour: 23}) {
+		t.Errorf("expected=23:00:00, actual=%s", actual)
+	}
+	if actual := tod.Add(49*time.Hour + 30*time.Minute); actual != (TimeOfDay{Hour: 23, Minute: 30}) {
+		t.Errorf("expected=23:30:00, actual=%s", actual)
+	}
+	if actual := (TimeOfDay{Hour: 1}).Sub(tod); actual != -21*time.Hour {
+		t.Errorf("expected=-21h, actual=%v", actual)
+	}
+}
+
+func TestTimeOfDay_Compare(t *testing.T) {
+	a := TimeOfDay{Hour: 9}
+	b := TimeOfDay{Hour: 9, Nanosecond: 1}
+	if !a.Before(b) || a.After(b) || b.Compare(a) != 1 || a.Compare(a) != 0 {
+		t.Errorf("unexpected comparison %s %s", a, b)
+	}
+}
+
+func TestTimeOfDay_On(t *testing.T) {
+	london, _ := time.LoadLocation("Europe/London")
+	tod := TimeOfDay{Hour: 1, Minute: 30}
+	if actual := tod.On(Date{2020, time.March, 29}, london).Format(time.RFC3339); actual != "2020-03-29T02:30:00+01:00" {
+		t.Errorf("expected=2020-03-29T02:30:00+01:00, actual=%s", actual)
+	}
+	if actual := tod.On(Date{2020, time.October, 25}, london).Format(time.RFC3339); actual != "2020-10-25T01:30:00+01:00" {
+		t.Errorf("expected=2020-10-25T01:30:00+01:00, actual=%s", actual)
+	}
+	tm := time.Date(2020, 4, 1, 18, 0, 0, 5, time.UTC)
+	if actual := TimeOfDayOf(tm).On(DateOf(tm), time.UTC); !actual.Equal(tm) {
+		t.Errorf("expected=%s, actual=%s", tm, actual)
+	}
+}
+
+func TestTimeOfDay_Marshal(t *testing.T) {
+	b, err := json.Marshal(map[string]TimeOfDay{"at": {Hour: 18, Nanosecond: 500000000}})
+	if err != nil || string(b) != `{"at":"18:00:00.5"}` {
+		t.Errorf("unexpected json %s %v", b, err)
+	}
+	var v map[string]TimeOfDay
+	if err = json.Unmarshal(b, &v); err != nil || v["at"] != (TimeOfDay{Hour: 18, Nanosecond: 500000000}) {
+		t.Errorf("unexpected value %v %v", v, err)
+	}
+	var tod TimeOfDay
+	if err = tod.Scan([]byte("09:15:00")); err != nil || tod != (TimeOfDay{Hour: 9, Minute: 15}) {
+		t.Errorf("expected=09:15:00, actual=%s %v", tod, err)
+	}
+	if value, _ := tod.Value(); value != "09:15:00" {
+		t.Errorf("expected=09:15:00, actual=%v", value)
+	}
+	if err = tod.Scan(1.5); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,97 @@
+package clock
+
+import (
+	"time"
+)
+
+// Clock 現在時刻とタイマーを提供する時計
+type Clock interface {
+	// Now 現在時刻を返す
+	Now() time.Time
+	// Since 指定日時からの経過時間を返す
+	Since(t time.Time) time.Duration
+	// After 指定時間経過後に現在時刻を送信するチャネルを返す
+	After(d time.Duration) <-chan time.Time
+	// NewTimer 指定時間経過後に発火するタイマーを生成する
+	NewTimer(d time.Duration) Timer
+	// NewTicker 指定間隔で発火するティッカーを生成する
+	NewTicker(d time.Duration) Ticker
+	// Sleep 指定時間待機する
+	Sleep(d time.Duration)
+}
+
+// Timer time.Timer の抽象
+type Timer interface {
+	// C 発火時刻を受信するチャネルを返す
+	C() <-chan time.Time
+	// Stop タイマーを停止する(停止前に稼働していた場合は true)
+	Stop() bool
+	// Reset タイマーを指定時間後に再設定する(再設定前に稼働していた場合は true)
+	Reset(d time.Duration) bool
+}
+
+// Ticker time.Ticker の抽象
+type Ticker interface {
+	// C 発火時刻を受信するチャネルを返す
+	C() <-chan time.Time
+	// Stop ティッカーを停止する
+	Stop()
+	// Reset ティッカーの間隔を再設定する
+	Reset(d time.Duration)
+}
+
+// New システム時計を返す
+func New() Clock {
+	return realClock{}
+}
+
+// OrNew 指定された時計を返す(nil の場合はシステム時計)
+func OrNew(c Clock) Clock {
+	if c == nil {
+		return New()
+	}
+	return c
+}
+
+// realClock time パッケージによる時計
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+func (realClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+type realTimer struct {
+	*time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
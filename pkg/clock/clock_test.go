@@ -0,0 +1,34 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	c := New()
+	begin := c.Now()
+	c.Sleep(time.Millisecond)
+	if d := c.Since(begin); d < time.Millisecond {
+		t.Errorf("expected>=%v, actual=%v", time.Millisecond, d)
+	}
+	timer := c.NewTimer(time.Millisecond)
+	<-timer.C()
+	if timer.Stop() {
+		t.Error("expected=false, actual=true")
+	}
+	ticker := c.NewTicker(time.Millisecond)
+	<-ticker.C()
+	ticker.Stop()
+	<-c.After(time.Millisecond)
+}
+
+func TestOrNew(t *testing.T) {
+	if _, ok := OrNew(nil).(realClock); !ok {
+		t.Error("expected realClock")
+	}
+	f := NewFake(time.Now())
+	if OrNew(f) != Clock(f) {
+		t.Error("expected fake clock")
+	}
+}
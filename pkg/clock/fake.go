@@ -0,0 +1,243 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fake テスト用の手動で進める時計
+//
+// 時刻は Advance または Set を呼び出した場合にのみ進む。
+// タイマー・ティッカーは時刻を進めた際に発火予定時刻の順(同時刻の場合は生成順)に発火する。
+type Fake struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	seq     uint64
+	waiters []*fakeWaiter
+}
+
+// NewFake 指定日時で停止している時計を生成する
+func NewFake(now time.Time) *Fake {
+	f := &Fake{now: now}
+	f.cond = sync.NewCond(&f.mu)
+	return f
+}
+
+// Now 現在時刻を返す
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Since 指定日時からの経過時間を返す
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+// After 指定時間経過後に現在時刻を送信するチャネルを返す
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	return f.NewTimer(d).C()
+}
+
+// NewTimer 指定時間経過後に発火するタイマーを生成する(0以下の場合は即座に発火する)
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	w := f.newWaiter(0)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.schedule(w, d)
+	return &fakeTimer{w}
+}
+
+// NewTicker 指定間隔で発火するティッカーを生成する
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	w := f.newWaiter(d)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.schedule(w, d)
+	return &fakeTicker{w}
+}
+
+// Sleep 時計が指定時間進むまで待機する(0以下の場合は即座に戻る)
+func (f *Fake) Sleep(d time.Duration) {
+	<-f.After(d)
+}
+
+// Advance 時計を指定時間進め、その間に予定されたタイマーを順に発火する
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.set(f.now.Add(d))
+}
+
+// Set 時計を指定日時に設定し、それまでに予定されたタイマーを順に発火する(過去の日時は無視する)
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.set(t)
+}
+
+// set 時計を指定日時に設定する(ロックを取得済みであること)
+func (f *Fake) set(t time.Time) {
+	for len(f.waiters) > 0 && !f.waiters[0].when.After(t) {
+		w := f.waiters[0]
+		f.waiters = f.waiters[1:]
+		if w.when.After(f.now) {
+			f.now = w.when
+		}
+		select {
+		case w.ch <- f.now:
+		default: // 受信されていない場合は time パッケージと同様に破棄する
+		}
+		if w.period > 0 {
+			w.when = w.when.Add(w.period)
+			f.insert(w)
+		} else {
+			w.active = false
+		}
+	}
+	if t.After(f.now) {
+		f.now = t
+	}
+}
+
+// Waiters 発火待ちのタイマー・ティッカーの数を返す
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
+
+// BlockUntil 発火待ちのタイマー・ティッカーが n 個以上になるまで待機する
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for len(f.waiters) < n {
+		f.cond.Wait()
+	}
+}
+
+func (f *Fake) newWaiter(period time.Duration) *fakeWaiter {
+	return &fakeWaiter{
+		clock:  f,
+		ch:     make(chan time.Time, 1),
+		period: period,
+	}
+}
+
+// schedule 発火予定時刻を設定する(ロックを取得済みであること)
+//
+// タイマーの指定時間が0以下の場合は time パッケージと同様に即座に発火する。
+func (f *Fake) schedule(w *fakeWaiter, d time.Duration) bool {
+	active := f.remove(w)
+	if d <= 0 && w.period == 0 {
+		select {
+		case w.ch <- f.now:
+		default:
+		}
+		return active
+	}
+	f.seq++
+	w.seq = f.seq
+	w.when = f.now.Add(d)
+	w.active = true
+	f.insert(w)
+	return active
+}
+
+func (f *Fake) insert(w *fakeWaiter) {
+	i := sort.Search(len(f.waiters), func(i int) bool {
+		v := f.waiters[i]
+		return v.when.After(w.when) || v.when.Equal(w.when) && v.seq > w.seq
+	})
+	f.waiters = append(f.waiters, nil)
+	copy(f.waiters[i+1:], f.waiters[i:])
+	f.waiters[i] = w
+	f.cond.Broadcast()
+}
+
+// remove 発火予定を取り消す(ロックを取得済みであること)
+//
+// Go 1.23 以降の time パッケージと同様に、受信されていない発火済みの時刻も破棄し、停止したものとして扱う。
+func (f *Fake) remove(w *fakeWaiter) bool {
+	drained := false
+	select {
+	case <-w.ch:
+		drained = true
+	default:
+	}
+	if !w.active {
+		return drained
+	}
+	for i, v := range f.waiters {
+		if v == w {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			break
+		}
+	}
+	w.active = false
+	return true
+}
+
+// fakeWaiter 発火待ちのタイマー・ティッカー
+type fakeWaiter struct {
+	clock  *Fake
+	ch     chan time.Time
+	when   time.Time
+	period time.Duration
+	seq    uint64
+	active bool
+}
+
+type fakeTimer struct {
+	w *fakeWaiter
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.w.ch
+}
+
+func (t *fakeTimer) Stop() bool {
+	f := t.w.clock
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.remove(t.w)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	f := t.w.clock
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.schedule(t.w, d)
+}
+
+type fakeTicker struct {
+	w *fakeWaiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.w.ch
+}
+
+func (t *fakeTicker) Stop() {
+	f := t.w.clock
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.remove(t.w)
+}
+
+func (t *fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("clock: non-positive interval for Ticker.Reset")
+	}
+	f := t.w.clock
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t.w.period = d
+	f.schedule(t.w, d)
+}
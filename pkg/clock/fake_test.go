@@ -0,0 +1,222 @@
+package clock
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFake_Now(t *testing.T) {
+	now, _ := time.Parse(time.RFC3339, "2020-04-01T17:00:00+09:00")
+	f := NewFake(now)
+	if !f.Now().Equal(now) {
+		t.Errorf("expected=%v, actual=%v", now, f.Now())
+	}
+	f.Advance(90 * time.Minute)
+	if d := f.Since(now); d != 90*time.Minute {
+		t.Errorf("expected=%v, actual=%v", 90*time.Minute, d)
+	}
+	f.Set(now)
+	if d := f.Since(now); d != 90*time.Minute {
+		t.Errorf("expected=%v, actual=%v", 90*time.Minute, d)
+	}
+}
+
+func TestFake_Timer(t *testing.T) {
+	now, _ := time.Parse(time.RFC3339, "2020-04-01T17:00:00+09:00")
+	f := NewFake(now)
+	timer := f.NewTimer(time.Hour)
+	f.Advance(59 * time.Minute)
+	select {
+	case <-timer.C():
+		t.Fatal("timer fired early")
+	default:
+	}
+	f.Advance(2 * time.Minute)
+	select {
+	case tm := <-timer.C():
+		if expected := now.Add(time.Hour); !tm.Equal(expected) {
+			t.Errorf("expected=%v, actual=%v", expected, tm)
+		}
+	default:
+		t.Fatal("timer did not fire")
+	}
+	if timer.Stop() {
+		t.Error("expected=false, actual=true")
+	}
+	if timer.Reset(time.Minute) {
+		t.Error("expected=false, actual=true")
+	}
+	if !timer.Stop() {
+		t.Error("expected=true, actual=false")
+	}
+	f.Advance(time.Hour)
+	select {
+	case <-timer.C():
+		t.Fatal("stopped timer fired")
+	default:
+	}
+}
+
+func TestFake_Ticker(t *testing.T) {
+	now, _ := time.Parse(time.RFC3339, "2020-04-01T17:00:00+09:00")
+	f := NewFake(now)
+	ticker := f.NewTicker(15 * time.Minute)
+	timer := f.NewTimer(20 * time.Minute)
+	var fired []time.Time
+	for i := 0; i < 4; i++ {
+		f.Advance(15 * time.Minute)
+		select {
+		case tm := <-ticker.C():
+			fired = append(fired, tm)
+		default:
+		}
+	}
+	if len(fired) != 4 {
+		t.Fatalf("expected=4, actual=%d", len(fired))
+	}
+	expected := now.Add(time.Hour)
+	if !fired[3].Equal(expected) {
+		t.Errorf("expected=%v, actual=%v", expected, fired[3])
+	}
+	if tm := <-timer.C(); !tm.Equal(now.Add(20 * time.Minute)) {
+		t.Errorf("expected=%v, actual=%v", now.Add(20*time.Minute), tm)
+	}
+	ticker.Reset(time.Hour)
+	f.Advance(30 * time.Minute)
+	select {
+	case <-ticker.C():
+		t.Fatal("ticker fired early")
+	default:
+	}
+	ticker.Stop()
+	if f.Waiters() != 0 {
+		t.Errorf("expected=0, actual=%d", f.Waiters())
+	}
+}
+
+func TestFake_Sleep(t *testing.T) {
+	now, _ := time.Parse(time.RFC3339, "2020-04-01T17:00:00+09:00")
+	f := NewFake(now)
+	var wg sync.WaitGroup
+	var woke time.Time
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		f.Sleep(time.Minute)
+		woke = f.Now()
+	}()
+	f.BlockUntil(1)
+	f.Advance(time.Minute)
+	wg.Wait()
+	if expected := now.Add(time.Minute); !woke.Equal(expected) {
+		t.Errorf("expected=%v, actual=%v", expected, woke)
+	}
+}
+
+func TestFake_Order(t *testing.T) {
+	now, _ := time.Parse(time.RFC3339, "2020-04-01T17:00:00+09:00")
+	f := NewFake(now)
+	timers := []Timer{f.NewTimer(3 * time.Second), f.NewTimer(time.Second), f.NewTimer(2 * time.Second)}
+	f.Advance(5 * time.Second)
+	for i, expected := range []time.Duration{3 * time.Second, time.Second, 2 * time.Second} {
+		if tm := <-timers[i].C(); !tm.Equal(now.Add(expected)) {
+			t.Errorf("[%d] expected=%v, actual=%v", i, now.Add(expected), tm)
+		}
+	}
+	if !f.Now().Equal(now.Add(5 * time.Second)) {
+		t.Errorf("expected=%v, actual=%v", now.Add(5*time.Second), f.Now())
+	}
+}
+
+func TestFake_Stale(t *testing.T) {
+	// 受信されていない発火済みの時刻は Reset, Stop で破棄される(Go 1.23 以降の time パッケージと同じ)
+	now, _ := time.Parse(time.RFC3339, "2020-04-01T17:00:00+09:00")
+	f := NewFake(now)
+	timer := f.NewTimer(time.Minute)
+	f.Advance(time.Minute)
+	if !timer.Reset(time.Hour) {
+		t.Error("expected=true, actual=false")
+	}
+	select {
+	case tm := <-timer.C():
+		t.Fatalf("stale time %v", tm)
+	default:
+	}
+	f.Advance(time.Hour)
+	select {
+	case tm := <-timer.C():
+		if expected := now.Add(61 * time.Minute); !tm.Equal(expected) {
+			t.Errorf("expected=%v, actual=%v", expected, tm)
+		}
+	default:
+		t.Fatal("timer did not fire")
+	}
+	timer.Reset(0)
+	if !timer.Stop() {
+		t.Error("expected=true, actual=false")
+	}
+	select {
+	case tm := <-timer.C():
+		t.Fatalf("stale time %v", tm)
+	default:
+	}
+
+	ticker := f.NewTicker(time.Minute)
+	f.Advance(time.Minute)
+	ticker.Reset(time.Hour)
+	select {
+	case tm := <-ticker.C():
+		t.Fatalf("stale time %v", tm)
+	default:
+	}
+	ticker.Reset(time.Minute)
+	f.Advance(time.Minute)
+	ticker.Stop()
+	select {
+	case tm := <-ticker.C():
+		t.Fatalf("stale time %v", tm)
+	default:
+	}
+}
+
+func TestFake_NonPositive(t *testing.T) {
+	now, _ := time.Parse(time.RFC3339, "2020-04-01T17:00:00+09:00")
+	f := NewFake(now)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f.Sleep(0)
+		if tm := <-f.After(-time.Second); !tm.Equal(now) {
+			t.Errorf("expected=%v, actual=%v", now, tm)
+		}
+		timer := f.NewTimer(time.Hour)
+		timer.Reset(0)
+		<-timer.C()
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("non-positive timer did not fire")
+	}
+	if n := f.Waiters(); n != 0 {
+		t.Errorf("expected=0, actual=%d", n)
+	}
+}
+
+func TestFake_AdvanceConcurrent(t *testing.T) {
+	now, _ := time.Parse(time.RFC3339, "2020-04-01T17:00:00+09:00")
+	f := NewFake(now)
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f.Advance(time.Minute)
+		}()
+	}
+	wg.Wait()
+	if d := f.Since(now); d != 100*time.Minute {
+		t.Errorf("expected=%v, actual=%v", 100*time.Minute, d)
+	}
+}
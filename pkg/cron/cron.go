@@ -0,0 +1,271 @@
+package cron
+
+import (
+	"errors"
+	"time"
+
+	"github.com/goccha/times/pkg/gauge"
+	"github.com/goccha/times/pkg/zone"
+)
+
+// searchYears 次の実行日時を探索する最大の年数(グレゴリオ暦の周期)
+const searchYears = 400
+
+// nthWeekday 第n曜日(n が -1 の場合は最終)
+type nthWeekday struct {
+	day time.Weekday
+	n   int
+}
+
+// Schedule cron 式による実行スケジュール
+//
+// 日と曜日の両方が指定された場合は、いずれかに一致する日に実行する。
+// 夏時間の切り替えで存在しない時刻には実行せず、重複する時刻には最初の1回のみ実行する。
+type Schedule struct {
+	spec            string
+	loc             *time.Location
+	seconds         bits
+	minutes         bits
+	hours           bits
+	monthDays       bits
+	months          bits
+	weekdays        bits
+	anyMonthDay     bool
+	anyWeekday      bool
+	lastDays        []int // L, L-n(月末からの日数)
+	lastWeekday     bool  // LW
+	nearestWeekdays []int // nW
+	nthWeekdays     []nthWeekday
+}
+
+// String 解析元の cron 式を返す
+func (s *Schedule) String() string {
+	return s.spec
+}
+
+// Location スケジュールを評価するタイムゾーンを返す
+func (s *Schedule) Location() *time.Location {
+	return s.loc
+}
+
+// Next t より後の最初の実行日時を返す(実行日時が無い場合はゼロ値)
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.In(s.loc)
+	day := civil(t)
+	limit := day.AddDate(searchYears, 0, 0)
+	for first := true; day.Before(limit); first = false {
+		if !s.months.has(int(day.Month())) {
+			day = time.Date(day.Year(), day.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if s.matchDay(day) {
+			if next, ok := s.nextInDay(day, t, first && !s.transition(day)); ok {
+				return next
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return time.Time{}
+}
+
+// Prev t より前の最後の実行日時を返す(実行日時が無い場合はゼロ値)
+func (s *Schedule) Prev(t time.Time) time.Time {
+	t = t.In(s.loc)
+	day := civil(t)
+	limit := day.AddDate(-searchYears, 0, 0)
+	for first := true; day.After(limit); first = false {
+		if !s.months.has(int(day.Month())) {
+			day = time.Date(day.Year(), day.Month(), 0, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if s.matchDay(day) {
+			if prev, ok := s.prevInDay(day, t, first && !s.transition(day)); ok {
+				return prev
+			}
+		}
+		day = day.AddDate(0, 0, -1)
+	}
+	return time.Time{}
+}
+
+// NextN t より後の実行日時を n 件返す
+func (s *Schedule) NextN(t time.Time, n int) []time.Time {
+	times := make([]time.Time, 0, n)
+	for len(times) < n {
+		t = s.Next(t)
+		if t.IsZero() {
+			break
+		}
+		times = append(times, t)
+	}
+	return times
+}
+
+// Between 期間内(開始日時を含み終了日時を含まない)の全ての実行日時を返す
+func (s *Schedule) Between(tg *gauge.TimeGauge) []time.Time {
+	var times []time.Time
+	s.Each(tg, func(t time.Time) bool {
+		times = append(times, t)
+		return true
+	})
+	return times
+}
+
+// Each 期間内(開始日時を含み終了日時を含まない)の実行日時を順に f に渡す(f が false を返した場合は終了する)
+func (s *Schedule) Each(tg *gauge.TimeGauge, f func(t time.Time) bool) {
+	end := tg.End()
+	for t := s.Next(tg.Begin().Add(-time.Nanosecond)); !t.IsZero() && t.Before(end); t = s.Next(t) {
+		if !f(t) {
+			return
+		}
+	}
+}
+
+// Contains 期間内(開始日時を含み終了日時を含まない)に実行日時があるかどうか
+func (s *Schedule) Contains(tg *gauge.TimeGauge) bool {
+	t := s.Next(tg.Begin().Add(-time.Nanosecond))
+	return !t.IsZero() && t.Before(tg.End())
+}
+
+// nextInDay 指定日の t より後の最初の実行日時を返す(bounded の場合は t の時刻以降のみ探索する)
+func (s *Schedule) nextInDay(day, t time.Time, bounded bool) (time.Time, bool) {
+	for h := 0; h < 24; h++ {
+		if !s.hours.has(h) || bounded && h < t.Hour() {
+			continue
+		}
+		hb := bounded && h == t.Hour()
+		for m := 0; m < 60; m++ {
+			if !s.minutes.has(m) || hb && m < t.Minute() {
+				continue
+			}
+			mb := hb && m == t.Minute()
+			for sec := 0; sec < 60; sec++ {
+				if !s.seconds.has(sec) || mb && sec < t.Second() {
+					continue
+				}
+				if c, ok := s.at(day, h, m, sec); ok && c.After(t) {
+					return c, true
+				}
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// prevInDay 指定日の t より前の最後の実行日時を返す(bounded の場合は t の時刻以前のみ探索する)
+func (s *Schedule) prevInDay(day, t time.Time, bounded bool) (time.Time, bool) {
+	for h := 23; h >= 0; h-- {
+		if !s.hours.has(h) || bounded && h > t.Hour() {
+			continue
+		}
+		hb := bounded && h == t.Hour()
+		for m := 59; m >= 0; m-- {
+			if !s.minutes.has(m) || hb && m > t.Minute() {
+				continue
+			}
+			mb := hb && m == t.Minute()
+			for sec := 59; sec >= 0; sec-- {
+				if !s.seconds.has(sec) || mb && sec > t.Second() {
+					continue
+				}
+				if c, ok := s.at(day, h, m, sec); ok && c.Before(t) {
+					return c, true
+				}
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// at 指定日の壁時計の時刻を返す(存在しない時刻の場合は false、重複する時刻の場合は早い方)
+func (s *Schedule) at(day time.Time, h, m, sec int) (time.Time, bool) {
+	t, err := zone.Date(day.Year(), day.Month(), day.Day(), h, m, sec, 0, s.loc, zone.Reject)
+	if errors.Is(err, zone.ErrAmbiguous) {
+		t, err = zone.Date(day.Year(), day.Month(), day.Day(), h, m, sec, 0, s.loc, zone.Earlier)
+	}
+	return t, err == nil
+}
+
+// transition 指定日にオフセットの切り替えがあるかどうか
+func (s *Schedule) transition(day time.Time) bool {
+	begin := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, s.loc)
+	_, end := begin.ZoneBounds()
+	return !end.IsZero() && end.Before(begin.Add(26*time.Hour))
+}
+
+// matchDay 日・曜日のフィールドに一致するかどうか
+func (s *Schedule) matchDay(day time.Time) bool {
+	dom := s.matchMonthDay(day)
+	dow := s.matchWeekday(day)
+	if s.anyMonthDay || s.anyWeekday {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+func (s *Schedule) matchMonthDay(day time.Time) bool {
+	d := day.Day()
+	if s.monthDays.has(d) {
+		return true
+	}
+	last := daysIn(day)
+	for _, n := range s.lastDays {
+		if d == last-n {
+			return true
+		}
+	}
+	if s.lastWeekday && d == nearestWeekday(day, last) {
+		return true
+	}
+	for _, n := range s.nearestWeekdays {
+		if n <= last && d == nearestWeekday(day, n) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Schedule) matchWeekday(day time.Time) bool {
+	wd := day.Weekday()
+	if s.weekdays.has(int(wd)) {
+		return true
+	}
+	d := day.Day()
+	for _, nth := range s.nthWeekdays {
+		if nth.day != wd {
+			continue
+		}
+		if nth.n < 0 && d+7 > daysIn(day) || nth.n > 0 && (d-1)/7+1 == nth.n {
+			return true
+		}
+	}
+	return false
+}
+
+// nearestWeekday 同じ月の n 日に最も近い平日を返す
+func nearestWeekday(day time.Time, n int) int {
+	last := daysIn(day)
+	switch time.Date(day.Year(), day.Month(), n, 0, 0, 0, 0, time.UTC).Weekday() {
+	case time.Saturday:
+		if n == 1 {
+			return 3
+		}
+		return n - 1
+	case time.Sunday:
+		if n == last {
+			return n - 2
+		}
+		return n + 1
+	}
+	return n
+}
+
+func daysIn(day time.Time) int {
+	return time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// civil 壁時計の日付を UTC の0時で返す
+func civil(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,158 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goccha/times/pkg/gauge"
+)
+
+func mustParse(t *testing.T, spec string) *Schedule {
+	t.Helper()
+	s, err := ParseInLocation(spec, time.UTC)
+	if err != nil {
+		t.Fatalf("%s: %v", spec, err)
+	}
+	return s
+}
+
+func date(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestSchedule_Next(t *testing.T) {
+	tests := []struct {
+		spec     string
+		from     string
+		expected string
+	}{
+		{"*/15 * * * *", "2020-04-01T17:07:00Z", "2020-04-01T17:15:00Z"},
+		{"*/15 * * * *", "2020-04-01T17:15:00Z", "2020-04-01T17:30:00Z"},
+		{"0 9 * * MON-FRI", "2020-04-03T10:00:00Z", "2020-04-06T09:00:00Z"},
+		{"30 */10 * * * *", "2020-04-01T17:00:30Z", "2020-04-01T17:10:30Z"},
+		{"@daily", "2020-04-01T17:00:00Z", "2020-04-02T00:00:00Z"},
+		{"@monthly", "2020-12-15T00:00:00Z", "2021-01-01T00:00:00Z"},
+		{"@weekly", "2020-04-01T00:00:00Z", "2020-04-05T00:00:00Z"},
+		{"0 0 29 2 *", "2020-03-01T00:00:00Z", "2024-02-29T00:00:00Z"},
+		{"0 0 L * *", "2020-02-01T00:00:00Z", "2020-02-29T00:00:00Z"},
+		{"0 0 L-2 * *", "2020-04-01T00:00:00Z", "2020-04-28T00:00:00Z"},
+		{"0 0 15W * *", "2020-08-01T00:00:00Z", "2020-08-14T00:00:00Z"},   // 15日は土曜日
+		{"0 0 1W * *", "2020-07-31T00:00:00Z", "2020-08-03T00:00:00Z"},    // 1日は土曜日
+		{"0 0 LW * *", "2020-05-01T00:00:00Z", "2020-05-29T00:00:00Z"},    // 31日は日曜日
+		{"0 0 * * 5L", "2020-04-01T00:00:00Z", "2020-04-24T00:00:00Z"},    // 最終金曜日
+		{"0 0 * * FRI#2", "2020-04-01T00:00:00Z", "2020-04-10T00:00:00Z"}, // 第2金曜日
+		{"0 0 13 * 5", "2020-04-01T00:00:00Z", "2020-04-03T00:00:00Z"},    // 13日または金曜日
+		{"0 0 * * 7", "2020-04-01T00:00:00Z", "2020-04-05T00:00:00Z"},
+	}
+	for _, test := range tests {
+		s := mustParse(t, test.spec)
+		if actual := s.Next(date(test.from)); !actual.Equal(date(test.expected)) {
+			t.Errorf("%s: expected=%s, actual=%s", test.spec, test.expected, actual)
+		}
+	}
+}
+
+func TestSchedule_Prev(t *testing.T) {
+	tests := []struct {
+		spec     string
+		from     string
+		expected string
+	}{
+		{"*/15 * * * *", "2020-04-01T17:07:00Z", "2020-04-01T17:00:00Z"},
+		{"*/15 * * * *", "2020-04-01T17:00:00Z", "2020-04-01T16:45:00Z"},
+		{"0 9 * * MON-FRI", "2020-04-06T08:00:00Z", "2020-04-03T09:00:00Z"},
+		{"0 0 29 2 *", "2024-02-28T00:00:00Z", "2020-02-29T00:00:00Z"},
+		{"0 0 L * *", "2020-03-15T00:00:00Z", "2020-02-29T00:00:00Z"},
+	}
+	for _, test := range tests {
+		s := mustParse(t, test.spec)
+		if actual := s.Prev(date(test.from)); !actual.Equal(date(test.expected)) {
+			t.Errorf("%s: expected=%s, actual=%s", test.spec, test.expected, actual)
+		}
+	}
+}
+
+func TestSchedule_Never(t *testing.T) {
+	s := mustParse(t, "0 0 30 2 *")
+	if actual := s.Next(date("2020-01-01T00:00:00Z")); !actual.IsZero() {
+		t.Errorf("expected=zero, actual=%s", actual)
+	}
+	if actual := s.Prev(date("2020-01-01T00:00:00Z")); !actual.IsZero() {
+		t.Errorf("expected=zero, actual=%s", actual)
+	}
+}
+
+func TestSchedule_NextN(t *testing.T) {
+	s := mustParse(t, "CRON_TZ=Asia/Tokyo 0 17 * * *")
+	actual := s.NextN(date("2020-04-01T00:00:00Z"), 5)
+	if len(actual) != 5 {
+		t.Fatalf("expected=5, actual=%d", len(actual))
+	}
+	for i, v := range actual {
+		expected := time.Date(2020, 4, 1+i, 8, 0, 0, 0, time.UTC)
+		if !v.Equal(expected) {
+			t.Errorf("[%d] expected=%s, actual=%s", i, expected, v)
+		}
+		if v.Location().String() != "Asia/Tokyo" {
+			t.Errorf("expected=Asia/Tokyo, actual=%s", v.Location())
+		}
+	}
+}
+
+func TestSchedule_Between(t *testing.T) {
+	s := mustParse(t, "0 * * * *")
+	tg := gauge.New(date("2020-04-01T17:00:00Z"), date("2020-04-01T20:00:00Z"))
+	actual := s.Between(tg)
+	expected := []string{"2020-04-01T17:00:00Z", "2020-04-01T18:00:00Z", "2020-04-01T19:00:00Z"}
+	if len(actual) != len(expected) {
+		t.Fatalf("expected=%v, actual=%v", expected, actual)
+	}
+	for i, v := range actual {
+		if !v.Equal(date(expected[i])) {
+			t.Errorf("[%d] expected=%s, actual=%s", i, expected[i], v)
+		}
+	}
+	if !s.Contains(tg) {
+		t.Errorf("expected=true, actual=false")
+	}
+	if s.Contains(gauge.New(date("2020-04-01T17:01:00Z"), date("2020-04-01T18:00:00Z"))) {
+		t.Errorf("expected=false, actual=true")
+	}
+	count := 0
+	s.Each(tg, func(time.Time) bool {
+		count++
+		return count < 2
+	})
+	if count != 2 {
+		t.Errorf("expected=2, actual=%d", count)
+	}
+}
+
+func TestSchedule_DST(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	// 2020-03-08 02:30 は存在しないため実行しない
+	s, _ := ParseInLocation("30 2 * * *", ny)
+	actual := s.Next(time.Date(2020, 3, 7, 12, 0, 0, 0, ny))
+	if expected := time.Date(2020, 3, 9, 2, 30, 0, 0, ny); !actual.Equal(expected) {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+	// 2020-11-01 01:30 は重複するため最初の1回のみ実行する
+	s, _ = ParseInLocation("30 1 * * *", ny)
+	first := s.Next(time.Date(2020, 10, 31, 12, 0, 0, 0, ny))
+	if expected := time.Date(2020, 11, 1, 5, 30, 0, 0, time.UTC); !first.Equal(expected) {
+		t.Errorf("expected=%s, actual=%s", expected, first)
+	}
+	if second := s.Next(first); !second.Equal(time.Date(2020, 11, 2, 1, 30, 0, 0, ny)) {
+		t.Errorf("expected=2020-11-02 01:30, actual=%s", second)
+	}
+	// 重複する時刻の2回目からの前回の実行日時
+	s, _ = ParseInLocation("30 * * * *", ny)
+	from := time.Date(2020, 11, 1, 6, 10, 0, 0, time.UTC) // 01:10 EST
+	if prev := s.Prev(from); !prev.Equal(time.Date(2020, 11, 1, 5, 30, 0, 0, time.UTC)) {
+		t.Errorf("expected=01:30 EDT, actual=%s", prev)
+	}
+}
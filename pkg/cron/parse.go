@@ -0,0 +1,276 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bits 値の集合
+type bits uint64
+
+func (b bits) has(v int) bool {
+	return b&(1<<uint(v)) != 0
+}
+
+// fieldRange フィールドの値の範囲
+type fieldRange struct {
+	name   string
+	min    int
+	max    int
+	names  map[string]int
+	sunday bool // 7 を日曜日(0)として扱う
+}
+
+var (
+	secondField  = fieldRange{name: "second", min: 0, max: 59}
+	minuteField  = fieldRange{name: "minute", min: 0, max: 59}
+	hourField    = fieldRange{name: "hour", min: 0, max: 23}
+	dayField     = fieldRange{name: "day of month", min: 1, max: 31}
+	monthField   = fieldRange{name: "month", min: 1, max: 12, names: monthNames}
+	weekdayField = fieldRange{name: "day of week", min: 0, max: 7, names: weekdayNames, sunday: true}
+)
+
+var monthNames = map[string]int{
+	"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+	"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+}
+
+var weekdayNames = map[string]int{
+	"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+}
+
+var macros = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+// Parse cron 式を解析する
+//
+// 標準の5フィールド(分 時 日 月 曜日)と、先頭に秒を加えた6フィールドの形式に対応する。
+// "CRON_TZ=Asia/Tokyo " の接頭辞でタイムゾーンを指定でき、指定が無い場合は time.Local で評価する。
+func Parse(spec string) (*Schedule, error) {
+	return ParseInLocation(spec, time.Local)
+}
+
+// ParseInLocation cron 式を解析し、タイムゾーンの指定が無い場合は loc で評価する
+func ParseInLocation(spec string, loc *time.Location) (*Schedule, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	s := &Schedule{spec: spec, loc: loc}
+	expr := strings.TrimSpace(spec)
+	for _, prefix := range []string{"CRON_TZ=", "TZ="} {
+		if !strings.HasPrefix(expr, prefix) {
+			continue
+		}
+		i := strings.IndexAny(expr, " \t")
+		if i < 0 {
+			return nil, fmt.Errorf("cron: missing fields in %q", spec)
+		}
+		l, err := time.LoadLocation(expr[len(prefix):i])
+		if err != nil {
+			return nil, fmt.Errorf("cron: invalid time zone in %q: %w", spec, err)
+		}
+		s.loc = l
+		expr = strings.TrimSpace(expr[i:])
+		break
+	}
+	if strings.HasPrefix(expr, "@") {
+		m, ok := macros[strings.ToLower(expr)]
+		if !ok {
+			return nil, fmt.Errorf("cron: unsupported macro %q", expr)
+		}
+		expr = m
+	}
+	fields := strings.Fields(expr)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("cron: expected 5 or 6 fields, found %d in %q", len(fields), spec)
+	}
+	var err error
+	if s.seconds, err = parseField(fields[0], secondField); err != nil {
+		return nil, err
+	}
+	if s.minutes, err = parseField(fields[1], minuteField); err != nil {
+		return nil, err
+	}
+	if s.hours, err = parseField(fields[2], hourField); err != nil {
+		return nil, err
+	}
+	if err = s.parseMonthDay(fields[3]); err != nil {
+		return nil, err
+	}
+	if s.months, err = parseField(fields[4], monthField); err != nil {
+		return nil, err
+	}
+	if err = s.parseWeekday(fields[5]); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// parseMonthDay 日のフィールドを解析する(L, L-n, LW, nW に対応)
+func (s *Schedule) parseMonthDay(field string) error {
+	if field == "*" || field == "?" {
+		s.anyMonthDay = true
+		s.monthDays = rangeBits(dayField.min, dayField.max)
+		return nil
+	}
+	for _, item := range strings.Split(field, ",") {
+		upper := strings.ToUpper(item)
+		switch {
+		case upper == "L":
+			s.lastDays = append(s.lastDays, 0)
+		case upper == "LW":
+			s.lastWeekday = true
+		case strings.HasPrefix(upper, "L-"):
+			n, err := strconv.Atoi(upper[2:])
+			if err != nil || n < 0 || n > 30 {
+				return fmt.Errorf("cron: invalid day of month %q", item)
+			}
+			s.lastDays = append(s.lastDays, n)
+		case strings.HasSuffix(upper, "W"):
+			n, err := strconv.Atoi(upper[:len(upper)-1])
+			if err != nil || n < dayField.min || n > dayField.max {
+				return fmt.Errorf("cron: invalid day of month %q", item)
+			}
+			s.nearestWeekdays = append(s.nearestWeekdays, n)
+		default:
+			b, err := parseField(item, dayField)
+			if err != nil {
+				return err
+			}
+			s.monthDays |= b
+		}
+	}
+	return nil
+}
+
+// parseWeekday 曜日のフィールドを解析する(nL, n#k に対応)
+func (s *Schedule) parseWeekday(field string) error {
+	if field == "*" || field == "?" {
+		s.anyWeekday = true
+		s.weekdays = rangeBits(0, 6)
+		return nil
+	}
+	for _, item := range strings.Split(field, ",") {
+		upper := strings.ToUpper(item)
+		switch {
+		case strings.Contains(upper, "#"):
+			i := strings.IndexByte(upper, '#')
+			day, err := parseValue(upper[:i], weekdayField)
+			if err != nil {
+				return err
+			}
+			n, err := strconv.Atoi(upper[i+1:])
+			if err != nil || n < 1 || n > 5 {
+				return fmt.Errorf("cron: invalid day of week %q", item)
+			}
+			s.nthWeekdays = append(s.nthWeekdays, nthWeekday{day: time.Weekday(day % 7), n: n})
+		case len(upper) > 1 && strings.HasSuffix(upper, "L"):
+			day, err := parseValue(upper[:len(upper)-1], weekdayField)
+			if err != nil {
+				return err
+			}
+			s.nthWeekdays = append(s.nthWeekdays, nthWeekday{day: time.Weekday(day % 7), n: -1})
+		default:
+			b, err := parseField(item, weekdayField)
+			if err != nil {
+				return err
+			}
+			if b.has(7) {
+				b = b&^(1<<7) | 1
+			}
+			s.weekdays |= b
+		}
+	}
+	return nil
+}
+
+// parseField "*", "1-5", "*/15", "MON-FRI", "1,15" 形式のフィールドを解析する
+func parseField(field string, r fieldRange) (bits, error) {
+	var b bits
+	for _, item := range strings.Split(field, ",") {
+		v, err := parseItem(item, r)
+		if err != nil {
+			return 0, err
+		}
+		b |= v
+	}
+	return b, nil
+}
+
+func parseItem(item string, r fieldRange) (bits, error) {
+	rng, step := item, 1
+	if i := strings.IndexByte(item, '/'); i >= 0 {
+		n, err := strconv.Atoi(item[i+1:])
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("cron: invalid step in %s %q", r.name, item)
+		}
+		rng, step = item[:i], n
+	}
+	lo, hi := r.min, r.max
+	if r.sunday {
+		hi = 6 // "*" は 0-6 とする
+	}
+	switch {
+	case rng == "*" || rng == "?":
+	case strings.Contains(rng, "-"):
+		i := strings.IndexByte(rng, '-')
+		var err error
+		if lo, err = parseValue(rng[:i], r); err != nil {
+			return 0, err
+		}
+		if hi, err = parseValue(rng[i+1:], r); err != nil {
+			return 0, err
+		}
+		if lo > hi {
+			return 0, fmt.Errorf("cron: invalid range in %s %q", r.name, item)
+		}
+	default:
+		v, err := parseValue(rng, r)
+		if err != nil {
+			return 0, err
+		}
+		lo = v
+		if step == 1 {
+			hi = v
+		} else if r.sunday {
+			hi = 7
+		}
+	}
+	var b bits
+	for v := lo; v <= hi; v += step {
+		b |= 1 << uint(v)
+	}
+	return b, nil
+}
+
+func parseValue(s string, r fieldRange) (int, error) {
+	if v, ok := r.names[strings.ToUpper(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < r.min || v > r.max {
+		return 0, fmt.Errorf("cron: invalid %s %q", r.name, s)
+	}
+	return v, nil
+}
+
+func rangeBits(lo, hi int) bits {
+	var b bits
+	for v := lo; v <= hi; v++ {
+		b |= 1 << uint(v)
+	}
+	return b
+}
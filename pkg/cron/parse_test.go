@@ -0,0 +1,85 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	s, err := Parse("CRON_TZ=America/New_York 0 0 9 * * 1-5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Location().String() != "America/New_York" {
+		t.Errorf("expected=America/New_York, actual=%s", s.Location())
+	}
+	if s.String() != "CRON_TZ=America/New_York 0 0 9 * * 1-5" {
+		t.Errorf("unexpected spec %s", s.String())
+	}
+	if s, _ = Parse("0 9 * * *"); s.Location() != time.Local {
+		t.Errorf("expected=Local, actual=%s", s.Location())
+	}
+}
+
+func TestParseField(t *testing.T) {
+	tests := []struct {
+		field    string
+		r        fieldRange
+		expected []int
+	}{
+		{"*/20", minuteField, []int{0, 20, 40}},
+		{"10-30/10", minuteField, []int{10, 20, 30}},
+		{"45/5", minuteField, []int{45, 50, 55}},
+		{"1,15,L", dayField, nil},
+		{"JAN,mar-MAY", monthField, []int{1, 3, 4, 5}},
+		{"FRI-SUN", weekdayField, nil},
+		{"5-7", weekdayField, []int{5, 6, 7}},
+		{"*", weekdayField, []int{0, 1, 2, 3, 4, 5, 6}},
+	}
+	for _, test := range tests {
+		b, err := parseField(test.field, test.r)
+		if test.expected == nil {
+			if err == nil {
+				t.Errorf("%s: expected error", test.field)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.field, err)
+			continue
+		}
+		var expected bits
+		for _, v := range test.expected {
+			expected |= 1 << uint(v)
+		}
+		if b != expected {
+			t.Errorf("%s: expected=%b, actual=%b", test.field, expected, b)
+		}
+	}
+}
+
+func TestParse_Error(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"@reboot",
+		"CRON_TZ=Nowhere/City * * * * *",
+		"CRON_TZ=UTC",
+		"* * 32W * *",
+		"* * L-31 * *",
+		"* * * * MON#6",
+		"* * * * XL",
+	} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("%q: expected error", spec)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package gauge
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrIncompatibleAggregator 集計方法が異なるため統合できない
+var ErrIncompatibleAggregator = errors.New("gauge: incompatible aggregator")
+
+// AggregatorOption 集計のオプション
+type AggregatorOption func(*Aggregator)
+
+// Approximate 分位点を指定した相対誤差(0 < accuracy < 1)の近似で求める
+//
+// 全ての値を保持せず、対数スケールのヒストグラムで集計するため使用するメモリが値の範囲で制限される。
+func Approximate(accuracy float64) AggregatorOption {
+	return func(a *Aggregator) {
+		if accuracy <= 0 || accuracy >= 1 {
+			return
+		}
+		a.hist = newHistogram(accuracy)
+		a.samples = nil
+	}
+}
+
+// Summary 集計結果
+type Summary struct {
+	Count  int64
+	Sum    time.Duration
+	Min    time.Duration
+	Max    time.Duration
+	Mean   time.Duration
+	StdDev time.Duration // 母標準偏差
+	P50    time.Duration
+	P95    time.Duration
+	P99    time.Duration
+}
+
+// Aggregator 期間の統計集計
+//
+// 複数の goroutine から同時に Add できる。
+type Aggregator struct {
+	mu      sync.Mutex
+	count   int64
+	sum     time.Duration
+	min     time.Duration
+	max     time.Duration
+	mean    float64
+	m2      float64
+	samples []time.Duration
+	sorted  bool
+	hist    *histogram
+}
+
+// NewAggregator 期間の統計集計を生成する(既定では全ての値を保持して正確な分位点を求める)
+func NewAggregator(opts ...AggregatorOption) *Aggregator {
+	a := &Aggregator{}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Add 期間を集計に追加する
+func (a *Aggregator) Add(tg *TimeGauge) {
+	a.AddDuration(tg.Duration())
+}
+
+// AddDuration 時間を集計に追加する
+func (a *Aggregator) AddDuration(d time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.count == 0 || d < a.min {
+		a.min = d
+	}
+	if a.count == 0 || d > a.max {
+		a.max = d
+	}
+	a.count++
+	a.sum += d
+	delta := float64(d) - a.mean
+	a.mean += delta / float64(a.count)
+	a.m2 += delta * (float64(d) - a.mean)
+	if a.hist != nil {
+		a.hist.add(d, 1)
+	} else {
+		a.samples = append(a.samples, d)
+		a.sorted = false
+	}
+}
+
+// Merge 他の集計結果を統合する
+//
+// 近似集計には正確な集計・同じ精度の近似集計を統合できる。正確な集計には正確な集計のみ統合できる。
+func (a *Aggregator) Merge(other *Aggregator) error {
+	if a == other {
+		return errors.New("gauge: cannot merge aggregator into itself")
+	}
+	other.mu.Lock()
+	o := Aggregator{
+		count: other.count,
+		sum:   other.sum,
+		min:   other.min,
+		max:   other.max,
+		mean:  other.mean,
+		m2:    other.m2,
+	}
+	if other.hist != nil {
+		o.hist = other.hist.clone()
+	} else {
+		o.samples = append([]time.Duration(nil), other.samples...)
+	}
+	other.mu.Unlock()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	switch {
+	case a.hist == nil && o.hist != nil:
+		return ErrIncompatibleAggregator
+	case a.hist != nil && o.hist != nil && a.hist.gamma != o.hist.gamma:
+		return ErrIncompatibleAggregator
+	}
+	if o.count == 0 {
+		return nil
+	}
+	if a.hist != nil {
+		if o.hist != nil {
+			a.hist.merge(o.hist)
+		} else {
+			for _, d := range o.samples {
+				a.hist.add(d, 1)
+			}
+		}
+	} else {
+		a.samples = append(a.samples, o.samples...)
+		a.sorted = false
+	}
+	if a.count == 0 || o.min < a.min {
+		a.min = o.min
+	}
+	if a.count == 0 || o.max > a.max {
+		a.max = o.max
+	}
+	n := float64(a.count + o.count)
+	delta := o.mean - a.mean
+	a.m2 += o.m2 + delta*delta*float64(a.count)*float64(o.count)/n
+	a.mean += delta * float64(o.count) / n
+	a.count += o.count
+	a.sum += o.sum
+	return nil
+}
+
+// Count 件数を返す
+func (a *Aggregator) Count() int64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.count
+}
+
+// Quantile 分位点(0 <= q <= 1)を返す
+//
+// 正確な集計では隣接する値の線形補間、近似集計では相対誤差の範囲内の値を返す。
+func (a *Aggregator) Quantile(q float64) time.Duration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.quantile(q)
+}
+
+// Summary 集計結果を返す
+func (a *Aggregator) Summary() Summary {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.count == 0 {
+		return Summary{}
+	}
+	return Summary{
+		Count:  a.count,
+		Sum:    a.sum,
+		Min:    a.min,
+		Max:    a.max,
+		Mean:   time.Duration(math.Round(a.mean)),
+		StdDev: time.Duration(math.Round(math.Sqrt(a.m2 / float64(a.count)))),
+		P50:    a.quantile(0.5),
+		P95:    a.quantile(0.95),
+		P99:    a.quantile(0.99),
+	}
+}
+
+// Reset 集計結果を破棄する
+func (a *Aggregator) Reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.count, a.sum, a.min, a.max, a.mean, a.m2 = 0, 0, 0, 0, 0, 0
+	a.samples = nil
+	if a.hist != nil {
+		a.hist = newHistogram(a.hist.accuracy)
+	}
+}
+
+// quantile 分位点を返す(ロックを取得済みであること)
+func (a *Aggregator) quantile(q float64) time.Duration {
+	if a.count == 0 {
+		return 0
+	}
+	if q <= 0 {
+		return a.min
+	}
+	if q >= 1 {
+		return a.max
+	}
+	if a.hist != nil {
+		d := a.hist.quantile(q, a.count)
+		if d < a.min {
+			return a.min
+		}
+		if d > a.max {
+			return a.max
+		}
+		return d
+	}
+	if !a.sorted {
+		sort.Slice(a.samples, func(i, j int) bool { return a.samples[i] < a.samples[j] })
+		a.sorted = true
+	}
+	pos := q * float64(len(a.samples)-1)
+	i := int(pos)
+	if i+1 >= len(a.samples) {
+		return a.samples[len(a.samples)-1]
+	}
+	frac := pos - float64(i)
+	lo, hi := a.samples[i], a.samples[i+1]
+	return lo + time.Duration(math.Round(frac*float64(hi-lo)))
+}
+
+// histogram 相対誤差を保証する対数スケールのヒストグラム
+type histogram struct {
+	accuracy float64
+	gamma    float64
+	logGamma float64
+	zero     int64 // 0 以下の値の件数
+	buckets  map[int]int64
+}
+
+func newHistogram(accuracy float64) *histogram {
+	gamma := (1 + accuracy) / (1 - accuracy)
+	return &histogram{
+		accuracy: accuracy,
+		gamma:    gamma,
+		logGamma: math.Log(gamma),
+		buckets:  make(map[int]int64),
+	}
+}
+
+func (h *histogram) add(d time.Duration, n int64) {
+	if d <= 0 {
+		h.zero += n
+		return
+	}
+	h.buckets[int(math.Ceil(math.Log(float64(d))/h.logGamma))] += n
+}
+
+func (h *histogram) merge(o *histogram) {
+	h.zero += o.zero
+	for k, v := range o.buckets {
+		h.buckets[k] += v
+	}
+}
+
+func (h *histogram) clone() *histogram {
+	c := *h
+	c.buckets = make(map[int]int64, len(h.buckets))
+	for k, v := range h.buckets {
+		c.buckets[k] = v
+	}
+	return &c
+}
+
+func (h *histogram) quantile(q float64, count int64) time.Duration {
+	rank := int64(q * float64(count-1))
+	if rank < h.zero {
+		return 0
+	}
+	keys := make([]int, 0, len(h.buckets))
+	for k := range h.buckets {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	n := h.zero
+	for _, k := range keys {
+		n += h.buckets[k]
+		if n > rank {
+			return time.Duration(math.Round(2 * math.Pow(h.gamma, float64(k)) / (h.gamma + 1)))
+		}
+	}
+	return 0
+}
@@ -0,0 +1,122 @@
+package gauge
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAggregator_Summary(t *testing.T) {
+	begin, _ := time.Parse(time.RFC3339, "2020-04-01T09:00:00+09:00")
+	a := NewAggregator()
+	for i := 1; i <= 100; i++ {
+		a.Add(New(begin, begin.Add(time.Duration(i)*time.Millisecond)))
+	}
+	s := a.Summary()
+	if s.Count != 100 {
+		t.Errorf("expected=100, actual=%d", s.Count)
+	}
+	if s.Sum != 5050*time.Millisecond {
+		t.Errorf("expected=%v, actual=%v", 5050*time.Millisecond, s.Sum)
+	}
+	if s.Min != time.Millisecond || s.Max != 100*time.Millisecond {
+		t.Errorf("expected=1ms,100ms, actual=%v,%v", s.Min, s.Max)
+	}
+	if s.Mean != 50500*time.Microsecond {
+		t.Errorf("expected=%v, actual=%v", 50500*time.Microsecond, s.Mean)
+	}
+	expected := time.Duration(math.Round(math.Sqrt((100*100-1)/12.0) * float64(time.Millisecond)))
+	if s.StdDev != expected {
+		t.Errorf("expected=%v, actual=%v", expected, s.StdDev)
+	}
+	if s.P50 != 50500*time.Microsecond {
+		t.Errorf("expected=%v, actual=%v", 50500*time.Microsecond, s.P50)
+	}
+	if s.P99 != 99010*time.Microsecond {
+		t.Errorf("expected=%v, actual=%v", 99010*time.Microsecond, s.P99)
+	}
+	if q := a.Quantile(0); q != time.Millisecond {
+		t.Errorf("expected=%v, actual=%v", time.Millisecond, q)
+	}
+	a.Reset()
+	if s = a.Summary(); s.Count != 0 {
+		t.Errorf("expected=0, actual=%d", s.Count)
+	}
+}
+
+func TestAggregator_Approximate(t *testing.T) {
+	const accuracy = 0.01
+	exact := NewAggregator()
+	approx := NewAggregator(Approximate(accuracy))
+	for i := 1; i <= 10000; i++ {
+		d := time.Duration(i*i) * time.Microsecond
+		exact.AddDuration(d)
+		approx.AddDuration(d)
+	}
+	for _, q := range []float64{0.5, 0.95, 0.99} {
+		e, a := exact.Quantile(q), approx.Quantile(q)
+		if math.Abs(float64(a-e))/float64(e) > accuracy*1.5 {
+			t.Errorf("q=%v expected~%v, actual=%v", q, e, a)
+		}
+	}
+	if len(approx.hist.buckets) > 2000 {
+		t.Errorf("expected bounded buckets, actual=%d", len(approx.hist.buckets))
+	}
+	if approx.Summary().Mean != exact.Summary().Mean {
+		t.Errorf("expected=%v, actual=%v", exact.Summary().Mean, approx.Summary().Mean)
+	}
+}
+
+func TestAggregator_Merge(t *testing.T) {
+	whole := NewAggregator()
+	parts := []*Aggregator{NewAggregator(), NewAggregator(), NewAggregator()}
+	for i := 0; i < 300; i++ {
+		d := time.Duration(i%17+i) * time.Second
+		whole.AddDuration(d)
+		parts[i%3].AddDuration(d)
+	}
+	merged := NewAggregator()
+	for _, p := range parts {
+		if err := merged.Merge(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if merged.Summary() != whole.Summary() {
+		t.Errorf("expected=%+v, actual=%+v", whole.Summary(), merged.Summary())
+	}
+
+	approx := NewAggregator(Approximate(0.01))
+	if err := approx.Merge(merged); err != nil {
+		t.Fatal(err)
+	}
+	if approx.Count() != 300 {
+		t.Errorf("expected=300, actual=%d", approx.Count())
+	}
+	if err := merged.Merge(approx); !errors.Is(err, ErrIncompatibleAggregator) {
+		t.Errorf("expected=%v, actual=%v", ErrIncompatibleAggregator, err)
+	}
+	if err := approx.Merge(NewAggregator(Approximate(0.02))); !errors.Is(err, ErrIncompatibleAggregator) {
+		t.Errorf("expected=%v, actual=%v", ErrIncompatibleAggregator, err)
+	}
+}
+
+func TestAggregator_Concurrent(t *testing.T) {
+	a := NewAggregator(Approximate(0.01))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				a.AddDuration(time.Duration(j) * time.Millisecond)
+				_ = a.Quantile(0.5)
+			}
+		}()
+	}
+	wg.Wait()
+	if a.Count() != 8000 {
+		t.Errorf("expected=8000, actual=%d", a.Count())
+	}
+}
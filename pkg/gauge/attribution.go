@@ -0,0 +1,91 @@
+package gauge
+
+import (
+	"time"
+
+	"github.com/goccha/times/pkg/zone"
+)
+
+// Attribution Split で分割した期間に割り当てる日付の決め方
+type Attribution int
+
+const (
+	// AttributeClosing 期間を閉じる基準時刻の日付(既定)
+	AttributeClosing Attribution = iota
+	// AttributeOpening 期間を開く基準時刻の日付
+	AttributeOpening
+	// AttributeStart 分割前の期間の開始日時の日付(全ての期間に同じ日付を割り当てる)
+	AttributeStart
+	// AttributeMajority 分割前の期間を最も長く含む日付(全ての期間に同じ日付を割り当てる、同じ長さの場合は早い日付)
+	AttributeMajority
+)
+
+// WithAttribution 分割した期間に割り当てる日付の決め方を指定する(既定は AttributeClosing)
+func WithAttribution(a Attribution) SplitOption {
+	return func(c *splitConfig) {
+		c.attribution = a
+	}
+}
+
+// WithKeyLayout 分割した期間の日付(Date)の書式を指定する(既定は "2006-01-02")
+func WithKeyLayout(layout string) SplitOption {
+	return func(c *splitConfig) {
+		c.layout = layout
+	}
+}
+
+// WithKeyFunc 分割した期間の日付(Date)を返す関数を指定する
+//
+// 関数には WithAttribution・WithKeyLayout による日付を割り当てた期間を渡す。
+func WithKeyFunc(f func(TimeGauge) string) SplitOption {
+	return func(c *splitConfig) {
+		c.key = f
+	}
+}
+
+// attribute 分割した期間に日付を割り当てる(days は各期間を閉じる基準時刻の日付)
+func (c splitConfig) attribute(t *TimeGauge, segments []TimeGauge, days []time.Time, b baseTime) {
+	switch c.attribution {
+	case AttributeOpening:
+		prev := b.prev(days[0])
+		for i := range days {
+			days[i], prev = prev, days[i]
+		}
+	case AttributeStart:
+		y, m, d := t.begin.In(b.loc).Date()
+		fill(days, time.Date(y, m, d, 0, 0, 0, 0, time.UTC))
+	case AttributeMajority:
+		fill(days, majority(t, b.loc))
+	}
+	for i := range segments {
+		segments[i].date = days[i].Format(c.layout)
+		if c.key != nil {
+			segments[i].date = c.key(segments[i])
+		}
+	}
+}
+
+func fill(days []time.Time, day time.Time) {
+	for i := range days {
+		days[i] = day
+	}
+}
+
+// majority 期間を最も長く含む loc の日付(UTC で表現した日付)を返す
+func majority(t *TimeGauge, loc *time.Location) time.Time {
+	b := newBaseTime(0, 0, 0, 0, loc, zone.Compatible)
+	var longest time.Duration
+	var result time.Time
+	begin := t.begin
+	for day, midnight := b.first(t.begin); begin.Before(t.end); day, midnight = b.next(day) {
+		end := midnight
+		if t.end.Before(end) {
+			end = t.end
+		}
+		if d := end.Sub(begin); d > longest {
+			longest, result = d, day.AddDate(0, 0, -1)
+		}
+		begin = midnight
+	}
+	return result
+}
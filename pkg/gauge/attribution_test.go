@@ -0,0 +1,63 @@
+package gauge
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goccha/times/pkg/zone"
+)
+
+func TestTimeGauge_SplitAttribution(t *testing.T) {
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	// 4/1 17:00 〜 4/2 03:00 を 18:00 で分割する(4/1 は 7時間、4/2 は 3時間)
+	rec := New(time.Date(2020, 4, 1, 17, 0, 0, 0, tokyo), time.Date(2020, 4, 2, 3, 0, 0, 0, tokyo))
+	tests := []struct {
+		name     string
+		opts     []SplitOption
+		expected []string
+	}{
+		{"closing", nil, []string{"2020-04-01", "2020-04-02"}},
+		{"opening", []SplitOption{WithAttribution(AttributeOpening)}, []string{"2020-03-31", "2020-04-01"}},
+		{"start", []SplitOption{WithAttribution(AttributeStart)}, []string{"2020-04-01", "2020-04-01"}},
+		{"majority", []SplitOption{WithAttribution(AttributeMajority)}, []string{"2020-04-01", "2020-04-01"}},
+		{"layout", []SplitOption{WithAttribution(AttributeOpening), WithKeyLayout("20060102")}, []string{"20200331", "20200401"}},
+		{"func", []SplitOption{WithKeyFunc(func(tg TimeGauge) string {
+			return tg.Date() + "@" + tg.Begin().Format("15:04")
+		})}, []string{"2020-04-01@17:00", "2020-04-02@18:00"}},
+	}
+	for _, tt := range tests {
+		times := rec.Split(18, 0, 0, 0, tokyo, tt.opts...)
+		actual := make([]string, len(times))
+		for i := range times {
+			actual[i] = times[i].Date()
+		}
+		if strings.Join(actual, ",") != strings.Join(tt.expected, ",") {
+			t.Errorf("%s: expected=%v, actual=%v", tt.name, tt.expected, actual)
+		}
+	}
+
+	// 4/1 20:00 〜 4/2 07:00 は 4/2 を最も長く含む
+	rec = New(time.Date(2020, 4, 1, 20, 0, 0, 0, tokyo), time.Date(2020, 4, 2, 7, 0, 0, 0, tokyo))
+	for _, v := range rec.Split(5, 0, 0, 0, tokyo, WithAttribution(AttributeMajority)) {
+		if v.Date() != "2020-04-02" {
+			t.Errorf("expected=2020-04-02, actual=%s", v.Date())
+		}
+	}
+}
+
+func TestTimeGauge_SplitAttributionDST(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	// 2020-03-08 02:30 は存在しないため、Reject では期間を開く基準時刻は 3/7 となる
+	rec := New(time.Date(2020, 3, 8, 12, 0, 0, 0, ny), time.Date(2020, 3, 9, 12, 0, 0, 0, ny))
+	times := rec.Split(2, 30, 0, 0, ny, WithDSTPolicy(zone.Reject), WithAttribution(AttributeOpening))
+	expected := []string{"2020-03-07", "2020-03-09"}
+	if len(times) != len(expected) {
+		t.Fatalf("expected=%v, actual=%v", expected, times)
+	}
+	for i, v := range times {
+		if v.Date() != expected[i] {
+			t.Errorf("[%d] expected=%s, actual=%s", i, expected[i], v.Date())
+		}
+	}
+}
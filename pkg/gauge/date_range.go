@@ -0,0 +1,151 @@
+package gauge
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/goccha/times/pkg/civil"
+	"github.com/goccha/times/pkg/weeks"
+)
+
+// DateRange 開始日・終了日を含む日付の範囲
+type DateRange struct {
+	start civil.Date
+	end   civil.Date
+}
+
+// NewDateRange 開始日から終了日まで(終了日を含む)の範囲を生成する
+func NewDateRange(start, end civil.Date) (DateRange, error) {
+	if !start.IsValid() || !end.IsValid() {
+		return DateRange{}, fmt.Errorf("gauge: invalid date range %s/%s", start, end)
+	}
+	if end.Before(start) {
+		return DateRange{}, fmt.Errorf("gauge: end date %s is before start date %s", end, start)
+	}
+	return DateRange{start: start, end: end}, nil
+}
+
+// DateRangeOf 期間が含まれる loc の日付の範囲を返す(期間が空の場合は false)
+func DateRangeOf(tg *TimeGauge, loc *time.Location) (DateRange, bool) {
+	if !tg.End().After(tg.Begin()) {
+		return DateRange{}, false
+	}
+	return DateRange{
+		start: civil.DateOf(tg.Begin().In(loc)),
+		end:   civil.DateOf(tg.End().Add(-time.Nanosecond).In(loc)),
+	}, true
+}
+
+// Start 開始日を返す
+func (r DateRange) Start() civil.Date {
+	return r.start
+}
+
+// End 終了日を返す
+func (r DateRange) End() civil.Date {
+	return r.end
+}
+
+// Days 日数を返す(開始日・終了日を含む)
+func (r DateRange) Days() int {
+	return r.end.DaysSince(r.start) + 1
+}
+
+// String "2020-04-01/2020-04-03" 形式の文字列を返す
+func (r DateRange) String() string {
+	return r.start.String() + "/" + r.end.String()
+}
+
+// Contains 指定した日付が範囲に含まれるかどうか
+func (r DateRange) Contains(d civil.Date) bool {
+	return !d.Before(r.start) && !d.After(r.end)
+}
+
+// Each 範囲の日付を順に f に渡す(f が false を返した場合は終了する)
+func (r DateRange) Each(f func(d civil.Date) bool) {
+	for d := r.start; !d.After(r.end); d = d.AddDays(1) {
+		if !f(d) {
+			return
+		}
+	}
+}
+
+// Dates 範囲の日付を返す
+func (r DateRange) Dates() []civil.Date {
+	dates := make([]civil.Date, 0, r.Days())
+	r.Each(func(d civil.Date) bool {
+		dates = append(dates, d)
+		return true
+	})
+	return dates
+}
+
+// DatesOn 範囲の日付のうち指定した曜日の日付を返す
+func (r DateRange) DatesOn(weekdays ...time.Weekday) []civil.Date {
+	var mask [7]bool
+	for _, w := range weekdays {
+		mask[w] = true
+	}
+	var dates []civil.Date
+	r.Each(func(d civil.Date) bool {
+		if mask[d.Weekday()] {
+			dates = append(dates, d)
+		}
+		return true
+	})
+	return dates
+}
+
+// Overlap 指定した範囲と重複しているかどうか
+func (r DateRange) Overlap(o DateRange) bool {
+	return !r.end.Before(o.start) && !o.end.Before(r.start)
+}
+
+// Intersect 指定した範囲と重複する範囲を返す(重複しない場合は false)
+func (r DateRange) Intersect(o DateRange) (DateRange, bool) {
+	if !r.Overlap(o) {
+		return DateRange{}, false
+	}
+	i := r
+	if o.start.After(i.start) {
+		i.start = o.start
+	}
+	if o.end.Before(i.end) {
+		i.end = o.end
+	}
+	return i, true
+}
+
+// SplitByWeek 範囲を週毎に分割する
+func (r DateRange) SplitByWeek(rule weeks.Rule) []DateRange {
+	return r.split(func(d civil.Date) civil.Date {
+		return rule.StartDate(d).AddDays(7)
+	})
+}
+
+// SplitByMonth 範囲を月毎に分割する
+func (r DateRange) SplitByMonth() []DateRange {
+	return r.split(func(d civil.Date) civil.Date {
+		return civil.Date{Year: d.Year, Month: d.Month, Day: 1}.AddDate(0, 1, 0)
+	})
+}
+
+// split 範囲を next が返す次の区間の開始日で分割する
+func (r DateRange) split(next func(d civil.Date) civil.Date) []DateRange {
+	var ranges []DateRange
+	for d := r.start; !d.After(r.end); {
+		n := next(d)
+		end := n.AddDays(-1)
+		if end.After(r.end) {
+			end = r.end
+		}
+		ranges = append(ranges, DateRange{start: d, end: end})
+		d = n
+	}
+	return ranges
+}
+
+// Gauge 開始日の0時から終了日の翌日の0時までの loc の期間を返す
+func (r DateRange) Gauge(loc *time.Location) *TimeGauge {
+	return New(r.start.In(loc), r.end.AddDays(1).In(loc))
+}
@@ -0,0 +1,126 @@
+package gauge
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goccha/times/pkg/civil"
+	"github.com/goccha/times/pkg/weeks"
+)
+
+func mustDateRange(t *testing.T, start, end string) DateRange {
+	t.Helper()
+	s, _ := civil.ParseDate(start)
+	e, _ := civil.ParseDate(end)
+	r, err := NewDateRange(s, e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func rangesString(ranges []DateRange) string {
+	s := ""
+	for _, r := range ranges {
+		s += "[" + r.String() + "]"
+	}
+	return s
+}
+
+func TestNewDateRange(t *testing.T) {
+	r := mustDateRange(t, "2020-04-01", "2020-04-03")
+	if r.Days() != 3 || r.String() != "2020-04-01/2020-04-03" {
+		t.Errorf("unexpected range %s %d", r, r.Days())
+	}
+	if r = mustDateRange(t, "2020-04-01", "2020-04-01"); r.Days() != 1 {
+		t.Errorf("expected=1, actual=%d", r.Days())
+	}
+	if r = mustDateRange(t, "2000-01-01", "2499-12-31"); r.Days() != 182622 {
+		t.Errorf("expected=182622, actual=%d", r.Days())
+	}
+	if _, err := NewDateRange(civil.Date{Year: 2020, Month: 4, Day: 3}, civil.Date{Year: 2020, Month: 4, Day: 1}); err == nil {
+		t.Error("expected error")
+	}
+	if _, err := NewDateRange(civil.Date{Year: 2020, Month: 2, Day: 30}, civil.Date{Year: 2020, Month: 4, Day: 1}); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestDateRange_Dates(t *testing.T) {
+	r := mustDateRange(t, "2020-02-28", "2020-03-01")
+	dates := r.Dates()
+	expected := []string{"2020-02-28", "2020-02-29", "2020-03-01"}
+	if len(dates) != len(expected) {
+		t.Fatalf("expected=%v, actual=%v", expected, dates)
+	}
+	for i, d := range dates {
+		if d.String() != expected[i] {
+			t.Errorf("[%d] expected=%s, actual=%s", i, expected[i], d)
+		}
+	}
+	if !r.Contains(civil.Date{Year: 2020, Month: 2, Day: 29}) || r.Contains(civil.Date{Year: 2020, Month: 3, Day: 2}) {
+		t.Error("unexpected Contains")
+	}
+	// 2020-04-01(水)から2020-04-12(日)までの平日
+	r = mustDateRange(t, "2020-04-01", "2020-04-12")
+	workdays := r.DatesOn(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)
+	if len(workdays) != 8 || workdays[0].String() != "2020-04-01" || workdays[7].String() != "2020-04-10" {
+		t.Errorf("unexpected workdays %v", workdays)
+	}
+}
+
+func TestDateRange_Intersect(t *testing.T) {
+	a := mustDateRange(t, "2020-04-01", "2020-04-10")
+	b := mustDateRange(t, "2020-04-10", "2020-04-20")
+	i, ok := a.Intersect(b)
+	if !ok || i.String() != "2020-04-10/2020-04-10" {
+		t.Errorf("expected=2020-04-10/2020-04-10, actual=%s %v", i, ok)
+	}
+	if _, ok = a.Intersect(mustDateRange(t, "2020-04-11", "2020-04-20")); ok {
+		t.Error("expected=false, actual=true")
+	}
+	if i, _ = b.Intersect(mustDateRange(t, "2020-04-01", "2020-04-30")); i != b {
+		t.Errorf("expected=%s, actual=%s", b, i)
+	}
+}
+
+func TestDateRange_Split(t *testing.T) {
+	r := mustDateRange(t, "2020-03-30", "2020-05-03")
+	expected := "[2020-03-30/2020-03-31][2020-04-01/2020-04-30][2020-05-01/2020-05-03]"
+	if actual := rangesString(r.SplitByMonth()); actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+	r = mustDateRange(t, "2020-04-01", "2020-04-14")
+	expected = "[2020-04-01/2020-04-05][2020-04-06/2020-04-12][2020-04-13/2020-04-14]"
+	if actual := rangesString(r.SplitByWeek(weeks.ISO)); actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+	expected = "[2020-04-01/2020-04-04][2020-04-05/2020-04-11][2020-04-12/2020-04-14]"
+	if actual := rangesString(r.SplitByWeek(weeks.Sunday)); actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+}
+
+func TestDateRange_Gauge(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	r := mustDateRange(t, "2020-03-07", "2020-03-08")
+	tg := r.Gauge(ny)
+	if tg.Begin().Format(time.RFC3339) != "2020-03-07T00:00:00-05:00" || tg.End().Format(time.RFC3339) != "2020-03-09T00:00:00-04:00" {
+		t.Errorf("unexpected gauge %s", tg.Interval())
+	}
+	if tg.Hours() != 47 {
+		t.Errorf("expected=47, actual=%v", tg.Hours())
+	}
+	back, ok := DateRangeOf(tg, ny)
+	if !ok || back != r {
+		t.Errorf("expected=%s, actual=%s", r, back)
+	}
+	// 東京の日付では7日から9日にまたがる
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	if back, _ = DateRangeOf(tg, tokyo); back.String() != "2020-03-07/2020-03-09" {
+		t.Errorf("expected=2020-03-07/2020-03-09, actual=%s", back)
+	}
+	if _, ok = DateRangeOf(New(tg.Begin(), tg.Begin()), ny); ok {
+		t.Error("expected=false, actual=true")
+	}
+}
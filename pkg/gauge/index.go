@@ -0,0 +1,235 @@
+package gauge
+
+import (
+	"iter"
+	"time"
+)
+
+// Index 期間の重複を検索する索引(区間木)
+//
+// At の境界の扱いは TimeGauge.Contains と同じ(開始日時・終了日時を含まない)。
+// Overlapping は半開区間 [begin, end) として判定するため、TimeGauge.Overlap と異なり同一の期間も重複とする。
+// 終了日時が開始日時より前の期間は検索されない。
+// 挿入・削除は O(log n)、検索は O(log n + k) で、結果は開始日時の順に返す。
+// 複数の goroutine から同時に更新する場合は呼び出し側で排他制御すること。
+type Index struct {
+	root *indexNode
+	seq  uint64
+	ids  map[*TimeGauge]uint64
+}
+
+// indexNode 開始日時・終了日時・挿入順で整列した AVL 木の節(max は部分木の終了日時の最大値)
+type indexNode struct {
+	tg     *TimeGauge
+	begin  time.Time
+	end    time.Time
+	seq    uint64
+	max    time.Time
+	height int
+	left   *indexNode
+	right  *indexNode
+}
+
+// NewIndex 期間の索引を生成する
+func NewIndex(gauges ...*TimeGauge) *Index {
+	x := &Index{ids: make(map[*TimeGauge]uint64, len(gauges))}
+	for _, tg := range gauges {
+		x.Insert(tg)
+	}
+	return x
+}
+
+// Len 登録されている期間の数を返す
+func (x *Index) Len() int {
+	return len(x.ids)
+}
+
+// Insert 期間を登録する(登録済みの場合は何もしない)
+func (x *Index) Insert(tg *TimeGauge) {
+	if x.ids == nil {
+		x.ids = make(map[*TimeGauge]uint64)
+	}
+	if _, ok := x.ids[tg]; ok {
+		return
+	}
+	x.seq++
+	x.ids[tg] = x.seq
+	x.root = x.root.insert(&indexNode{tg: tg, begin: tg.begin, end: tg.end, seq: x.seq, max: tg.end, height: 1})
+}
+
+// Delete 期間の登録を削除する(登録されていない場合は false)
+func (x *Index) Delete(tg *TimeGauge) bool {
+	seq, ok := x.ids[tg]
+	if !ok {
+		return false
+	}
+	delete(x.ids, tg)
+	x.root = x.root.delete(tg.begin, tg.end, seq)
+	return true
+}
+
+// At 指定した日時を含む期間(TimeGauge.Contains が true となる期間)を返す
+func (x *Index) At(t time.Time) []*TimeGauge {
+	result := make([]*TimeGauge, 0)
+	x.root.within(t, t, func(tg *TimeGauge) {
+		if tg.Contains(t) {
+			result = append(result, tg)
+		}
+	})
+	return result
+}
+
+// Overlapping 指定した期間と重複する期間を返す(end が begin より前の場合は空)
+//
+// 半開区間 [begin, end) として、開始日時が end より前かつ終了日時が begin より後の期間を重複とする。
+// 同一の期間も重複となるため、予定の重複(二重予約)の検出に使用できる。境界が接するだけの期間は重複としない。
+func (x *Index) Overlapping(begin, end time.Time) []*TimeGauge {
+	result := make([]*TimeGauge, 0)
+	if end.Before(begin) {
+		return result
+	}
+	x.root.within(begin, end, func(tg *TimeGauge) {
+		result = append(result, tg)
+	})
+	return result
+}
+
+// All 登録されている期間を開始日時の順に返す
+func (x *Index) All() iter.Seq[*TimeGauge] {
+	return func(yield func(*TimeGauge) bool) {
+		x.root.each(yield)
+	}
+}
+
+// within 開始日時が end より前かつ終了日時が begin より後の期間を開始日時の順に f に渡す
+//
+// Contains が true となる期間は必ずこの条件を満たすため、At の候補の絞り込みにも使用する。
+func (n *indexNode) within(begin, end time.Time, f func(tg *TimeGauge)) {
+	if n == nil || !n.max.After(begin) {
+		return
+	}
+	n.left.within(begin, end, f)
+	if !n.begin.Before(end) {
+		return // 右の部分木の開始日時は全て end 以降
+	}
+	if n.end.After(begin) {
+		f(n.tg)
+	}
+	n.right.within(begin, end, f)
+}
+
+func (n *indexNode) each(f func(tg *TimeGauge) bool) bool {
+	if n == nil {
+		return true
+	}
+	return n.left.each(f) && f(n.tg) && n.right.each(f)
+}
+
+// compare 開始日時・終了日時・挿入順で比較する
+func (n *indexNode) compare(begin, end time.Time, seq uint64) int {
+	if c := n.begin.Compare(begin); c != 0 {
+		return c
+	}
+	if c := n.end.Compare(end); c != 0 {
+		return c
+	}
+	switch {
+	case n.seq < seq:
+		return -1
+	case n.seq > seq:
+		return 1
+	}
+	return 0
+}
+
+func (n *indexNode) insert(v *indexNode) *indexNode {
+	if n == nil {
+		return v
+	}
+	if n.compare(v.begin, v.end, v.seq) > 0 {
+		n.left = n.left.insert(v)
+	} else {
+		n.right = n.right.insert(v)
+	}
+	return n.balance()
+}
+
+func (n *indexNode) delete(begin, end time.Time, seq uint64) *indexNode {
+	if n == nil {
+		return nil
+	}
+	switch c := n.compare(begin, end, seq); {
+	case c > 0:
+		n.left = n.left.delete(begin, end, seq)
+	case c < 0:
+		n.right = n.right.delete(begin, end, seq)
+	default:
+		if n.left == nil {
+			return n.right
+		}
+		if n.right == nil {
+			return n.left
+		}
+		m := n.right
+		for m.left != nil {
+			m = m.left
+		}
+		n.tg, n.begin, n.end, n.seq = m.tg, m.begin, m.end, m.seq
+		n.right = n.right.delete(m.begin, m.end, m.seq)
+	}
+	return n.balance()
+}
+
+func (n *indexNode) getHeight() int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+// update 高さと終了日時の最大値を再計算する
+func (n *indexNode) update() {
+	n.height = 1 + max(n.left.getHeight(), n.right.getHeight())
+	n.max = n.end
+	if n.left != nil && n.left.max.After(n.max) {
+		n.max = n.left.max
+	}
+	if n.right != nil && n.right.max.After(n.max) {
+		n.max = n.right.max
+	}
+}
+
+func (n *indexNode) rotateLeft() *indexNode {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	n.update()
+	r.update()
+	return r
+}
+
+func (n *indexNode) rotateRight() *indexNode {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	n.update()
+	l.update()
+	return l
+}
+
+func (n *indexNode) balance() *indexNode {
+	n.update()
+	switch d := n.left.getHeight() - n.right.getHeight(); {
+	case d > 1:
+		if n.left.left.getHeight() < n.left.right.getHeight() {
+			n.left = n.left.rotateLeft()
+		}
+		return n.rotateRight()
+	case d < -1:
+		if n.right.right.getHeight() < n.right.left.getHeight() {
+			n.right = n.right.rotateRight()
+		}
+		return n.rotateLeft()
+	}
+	return n
+}
@@ -0,0 +1,171 @@
+package gauge
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+var indexBase = time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)
+
+// randomGauges 1年間に開始する最大 maxLen 分の期間を n 個生成する
+func randomGauges(r *rand.Rand, n int, maxLen int) []*TimeGauge {
+	gauges := make([]*TimeGauge, n)
+	for i := range gauges {
+		begin := indexBase.Add(time.Duration(r.Intn(365*24*60)) * time.Minute)
+		gauges[i] = New(begin, begin.Add(time.Duration(r.Intn(maxLen)+1)*time.Minute))
+	}
+	return gauges
+}
+
+// scan 線形探索で半開区間として重複する期間を返す
+func scan(gauges []*TimeGauge, begin, end time.Time) []*TimeGauge {
+	result := make([]*TimeGauge, 0)
+	for _, tg := range gauges {
+		if tg.Begin().Before(end) && tg.End().After(begin) {
+			result = append(result, tg)
+		}
+	}
+	return result
+}
+
+// scanAt 線形探索で日時を含む期間を返す
+func scanAt(gauges []*TimeGauge, tm time.Time) []*TimeGauge {
+	result := make([]*TimeGauge, 0)
+	for _, tg := range gauges {
+		if tg.Contains(tm) {
+			result = append(result, tg)
+		}
+	}
+	return result
+}
+
+func sortGauges(gauges []*TimeGauge) {
+	slices.SortStableFunc(gauges, func(a, b *TimeGauge) int {
+		if c := a.begin.Compare(b.begin); c != 0 {
+			return c
+		}
+		return a.end.Compare(b.end)
+	})
+}
+
+func TestIndex(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	gauges := randomGauges(r, 2000, 24*60)
+	x := NewIndex(gauges...)
+	x.Insert(gauges[0])
+	if x.Len() != len(gauges) {
+		t.Fatalf("expected=%d, actual=%d", len(gauges), x.Len())
+	}
+	// 半分を削除する
+	for _, tg := range gauges[:1000] {
+		if !x.Delete(tg) {
+			t.Fatal("expected deleted")
+		}
+	}
+	if x.Delete(gauges[0]) {
+		t.Error("expected not deleted")
+	}
+	rest := slices.Clone(gauges[1000:])
+	sortGauges(rest)
+	for i := 0; i < 200; i++ {
+		begin := indexBase.Add(time.Duration(r.Intn(365*24*60)) * time.Minute)
+		end := begin.Add(time.Duration(r.Intn(3*24*60)) * time.Minute)
+		expected := scan(rest, begin, end)
+		if actual := x.Overlapping(begin, end); !slices.Equal(actual, expected) {
+			t.Fatalf("Overlapping(%s, %s): expected=%d, actual=%d", begin, end, len(expected), len(actual))
+		}
+		expected = scanAt(rest, begin)
+		if actual := x.At(begin); !slices.Equal(actual, expected) {
+			t.Fatalf("At(%s): expected=%d, actual=%d", begin, len(expected), len(actual))
+		}
+	}
+	if actual := slices.Collect(x.All()); !slices.Equal(actual, rest) {
+		t.Error("All must return gauges in order")
+	}
+}
+
+func TestIndex_Boundary(t *testing.T) {
+	// 境界が一致する期間・長さ0の期間を含めて TimeGauge.Contains, 半開区間の重複と一致すること
+	var gauges []*TimeGauge
+	for b := 0; b < 4; b++ {
+		for e := b; e < 4; e++ {
+			gauges = append(gauges, New(indexBase.Add(time.Duration(b)*time.Hour), indexBase.Add(time.Duration(e)*time.Hour)))
+		}
+	}
+	var x Index
+	for _, tg := range gauges {
+		x.Insert(tg)
+	}
+	sortGauges(gauges)
+	for i := -1; i <= 8; i++ {
+		tm := indexBase.Add(time.Duration(i) * 30 * time.Minute)
+		if actual, expected := x.At(tm), scanAt(gauges, tm); !slices.Equal(actual, expected) {
+			t.Errorf("At(%s): expected=%v, actual=%v", tm, expected, actual)
+		}
+		for j := i; j <= 8; j++ {
+			end := indexBase.Add(time.Duration(j) * 30 * time.Minute)
+			if actual, expected := x.Overlapping(tm, end), scan(gauges, tm, end); !slices.Equal(actual, expected) {
+				t.Errorf("Overlapping(%s, %s): expected=%v, actual=%v", tm, end, expected, actual)
+			}
+		}
+	}
+	tg := New(indexBase, indexBase.Add(time.Hour))
+	x = Index{}
+	x.Insert(tg)
+	if actual := x.At(indexBase); len(actual) != 0 || tg.Contains(indexBase) {
+		t.Errorf("expected=0, actual=%d", len(actual))
+	}
+	if actual := x.Overlapping(indexBase.Add(time.Hour), indexBase); len(actual) != 0 {
+		t.Errorf("expected=0, actual=%d", len(actual))
+	}
+	// 同一の期間は重複とし、境界が接するだけの期間は重複としない
+	if actual := x.Overlapping(indexBase, indexBase.Add(time.Hour)); len(actual) != 1 || actual[0] != tg {
+		t.Errorf("expected=[%v], actual=%v", tg, actual)
+	}
+	if actual := x.Overlapping(indexBase.Add(time.Hour), indexBase.Add(2*time.Hour)); len(actual) != 0 {
+		t.Errorf("expected=0, actual=%d", len(actual))
+	}
+}
+
+func benchmarkGauges() ([]*TimeGauge, []time.Time) {
+	r := rand.New(rand.NewSource(1))
+	gauges := randomGauges(r, 200000, 4*60)
+	queries := make([]time.Time, 1024)
+	for i := range queries {
+		queries[i] = indexBase.Add(time.Duration(r.Intn(365*24*60)) * time.Minute)
+	}
+	return gauges, queries
+}
+
+func BenchmarkIndex_Overlapping(b *testing.B) {
+	gauges, queries := benchmarkGauges()
+	x := NewIndex(gauges...)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q := queries[i%len(queries)]
+		_ = x.Overlapping(q, q.Add(time.Hour))
+	}
+}
+
+func BenchmarkScan_Overlapping(b *testing.B) {
+	gauges, queries := benchmarkGauges()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q := queries[i%len(queries)]
+		_ = scan(gauges, q, q.Add(time.Hour))
+	}
+}
+
+func BenchmarkIndex_Insert(b *testing.B) {
+	gauges, _ := benchmarkGauges()
+	b.ResetTimer()
+	x := NewIndex()
+	for i := 0; i < b.N; i++ {
+		if i%len(gauges) == 0 {
+			x = NewIndex()
+		}
+		x.Insert(gauges[i%len(gauges)])
+	}
+}
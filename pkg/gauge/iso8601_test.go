@@ -0,0 +1,281 @@
+package gauge
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParseInterval(t *testing.T) {
+	begin, _ := time.Parse(time.RFC3339, "2020-04-01T17:00:00+09:00")
+	end, _ := time.Parse(time.RFC3339, "2020-04-02T01:00:00+09:00")
+	tests := []string{
+		"2020-04-01T17:00:00+09:00/2020-04-02T01:00:00+09:00",
+		"2020-04-01T17:00+09:00/PT8H",
+		"PT8H/2020-04-02T01:00:00+09:00",
+		"20200401T170000+0900/20200402T010000+0900",
+		"2020-04-01T08:00:00Z/PT480M",
+	}
+	for _, s := range tests {
+		tg, err := ParseInterval(s)
+		if err != nil {
+			t.Errorf("%s: %v", s, err)
+			continue
+		}
+		if !tg.Begin().Equal(begin) || !tg.End().Equal(end) {
+			t.Errorf("%s: expected=%v/%v, actual=%v/%v", s, begin, end, tg.Begin(), tg.End())
+		}
+	}
+}
+
+func TestParseInterval_Calendar(t *testing.T) {
+	tg, err := ParseInterval("2020-01-31T09:00:00+09:00/P1M")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "2020-03-02T09:00:00+09:00"
+	if actual := tg.End().Format(time.RFC3339); actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+	tg, err = ParseInterval("P1D/2020-04-02T00:00:00+09:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "2020-04-01T00:00:00+09:00"
+	if actual := tg.Begin().Format(time.RFC3339); actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+}
+
+func TestParseInterval_Location(t *testing.T) {
+	loc := time.FixedZone("JST", 9*60*60)
+	tg, err := ParseInterval("2020-04-01T09:00/PT1H", InLocation(loc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "2020-04-01T10:00:00+09:00"
+	if actual := tg.End().Format(time.RFC3339); actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+}
+
+func TestParseInterval_Strict(t *testing.T) {
+	tests := []string{
+		"2020-04-01 17:00:00+09:00/PT8H",
+		"2020-04-01T17:00:00+09:00--PT8H",
+		"2020-04-01T17:00:00+09:00/pt8h",
+		"2020-04-01T17:00:00+09:00/18:00",
+		"2020-04-01T17:00:00+09:00/PT1.5H30M",
+		"2020-04-01T1700+09:00/PT8H",
+		"PT1H/PT8H",
+		"2020-04-02T01:00:00+09:00/2020-04-01T17:00:00+09:00",
+		"2020-04-01T17:00:00+09:00",
+		"2020-04-01T17:00:00+09:00/P1.5D",
+	}
+	for _, s := range tests {
+		if _, err := ParseInterval(s); err == nil {
+			t.Errorf("%s: expected error", s)
+		}
+	}
+}
+
+func TestParseInterval_Lenient(t *testing.T) {
+	begin, _ := time.Parse(time.RFC3339, "2020-04-01T17:00:00+09:00")
+	end, _ := time.Parse(time.RFC3339, "2020-04-01T18:30:00+09:00")
+	tests := []string{
+		"2020-04-01 17:00:00+09:00/pt1h30m",
+		"2020-04-01t17:00+09:00--2020-04-01t18:30+09:00",
+		"2020-04-01T17:00:00+09:00/18:30",
+		"2020-04-01T17:00:00+09:00/PT1,5H",
+	}
+	for _, s := range tests {
+		tg, err := ParseInterval(s, Lenient())
+		if err != nil {
+			t.Errorf("%s: %v", s, err)
+			continue
+		}
+		if !tg.Begin().Equal(begin) || !tg.End().Equal(end) {
+			t.Errorf("%s: expected=%v/%v, actual=%v/%v", s, begin, end, tg.Begin(), tg.End())
+		}
+	}
+}
+
+func TestTimeGauge_FormatInterval(t *testing.T) {
+	begin, _ := time.Parse(time.RFC3339, "2020-04-01T17:00:00+09:00")
+	end, _ := time.Parse(time.RFC3339, "2020-04-02T01:30:00.5+09:00")
+	rec := New(begin, end)
+	tests := map[IntervalLayout]string{
+		StartEnd:      "2020-04-01T17:00:00+09:00/2020-04-02T01:30:00.5+09:00",
+		StartDuration: "2020-04-01T17:00:00+09:00/PT8H30M0.5S",
+		DurationEnd:   "PT8H30M0.5S/2020-04-02T01:30:00.5+09:00",
+	}
+	for layout, expected := range tests {
+		actual := rec.FormatInterval(layout)
+		if actual != expected {
+			t.Errorf("expected=%s, actual=%s", expected, actual)
+		}
+		tg, err := ParseInterval(actual)
+		if err != nil {
+			t.Errorf("%s: %v", actual, err)
+			continue
+		}
+		if !tg.Begin().Equal(begin) || !tg.End().Equal(end) {
+			t.Errorf("%s: round trip mismatch %v/%v", actual, tg.Begin(), tg.End())
+		}
+	}
+}
+
+func TestParsePeriod(t *testing.T) {
+	tests := map[string]Period{
+		"P1Y2M3DT4H5M6S": {Years: 1, Months: 2, Days: 3, Time: 4*time.Hour + 5*time.Minute + 6*time.Second},
+		"P2W":            {Weeks: 2},
+		"PT0.5S":         {Time: 500 * time.Millisecond},
+		"PT36H":          {Time: 36 * time.Hour},
+	}
+	for s, expected := range tests {
+		actual, err := ParsePeriod(s)
+		if err != nil {
+			t.Errorf("%s: %v", s, err)
+			continue
+		}
+		if actual != expected {
+			t.Errorf("expected=%+v, actual=%+v", expected, actual)
+		}
+		if actual.String() != s {
+			t.Errorf("expected=%s, actual=%s", s, actual.String())
+		}
+	}
+	for _, s := range []string{"P", "PT", "P1H", "PT1D", "P1M1Y", "1D"} {
+		if _, err := ParsePeriod(s); err == nil {
+			t.Errorf("%s: expected error", s)
+		}
+	}
+}
+
+func TestPeriod_String_Negative(t *testing.T) {
+	tests := []struct {
+		period   Period
+		expected string
+	}{
+		{Period{Time: -time.Hour}, "-PT1H"},
+		{Period{Days: -1, Time: -90 * time.Minute}, "-P1DT1H30M"},
+		{Period{Years: -1, Months: -2}, "-P1Y2M"},
+		{Period{Days: 1, Time: -time.Hour}, "P1DT-1H"},
+		{Period{Days: 1, Time: -90 * time.Minute}, "P1DT-1H-30M"},
+		{Period{Days: 1, Time: -1500 * time.Millisecond}, "P1DT-1.5S"},
+		{Period{Days: -1, Time: time.Hour}, "P-1DT1H"},
+	}
+	for _, tt := range tests {
+		actual := tt.period.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%s, actual=%s", tt.expected, actual)
+		}
+		// 出力した文字列は解析できること
+		if p, err := ParsePeriod(actual); err != nil || p != tt.period {
+			t.Errorf("%s: expected=%+v, actual=%+v (%v)", actual, tt.period, p, err)
+		}
+	}
+	for _, d := range []time.Duration{-1500 * time.Millisecond, -90 * time.Minute, math.MinInt64} {
+		s := FormatDuration(d)
+		if p, err := ParsePeriod(s); err != nil || p != (Period{Time: d}) {
+			t.Errorf("%s: expected=%v, actual=%v (%v)", s, d, p.Time, err)
+		}
+	}
+	if actual := FormatDuration(-1500 * time.Millisecond); actual != "-PT1.5S" {
+		t.Errorf("expected=-PT1.5S, actual=%s", actual)
+	}
+	// 負の期間の期間文字列は終了日時が開始日時より前になる
+	begin, _ := time.Parse(time.RFC3339, "2020-04-01T17:00:00+09:00")
+	tg := New(begin, begin.Add(-time.Hour))
+	for _, layout := range []IntervalLayout{StartEnd, StartDuration, DurationEnd} {
+		s := tg.FormatInterval(layout)
+		if _, err := ParseInterval(s); !errors.Is(err, ErrInverted) {
+			t.Errorf("%s: expected=%v, actual=%v", s, ErrInverted, err)
+		}
+	}
+}
+
+func TestParseRepeating(t *testing.T) {
+	loc := time.FixedZone("JST", 9*60*60)
+	r, err := ParseRepeating("R5/2020-04-01T09:00/P1W", InLocation(loc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Count() != 5 {
+		t.Errorf("expected=5, actual=%d", r.Count())
+	}
+	times := r.Expand(0)
+	if len(times) != 5 {
+		t.Fatalf("expected=5, actual=%d", len(times))
+	}
+	expected := "2020-04-29T09:00:00+09:00"
+	if actual := times[4].Begin().Format(time.RFC3339); actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+	expected = "2020-05-06T09:00:00+09:00"
+	if actual := times[4].End().Format(time.RFC3339); actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+	expected = "R5/2020-04-01T09:00:00+09:00/P1W"
+	if actual := r.String(); actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+}
+
+func TestParseRepeating_Unbounded(t *testing.T) {
+	r, err := ParseRepeating("R/PT8H/2020-04-02T01:00:00+09:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Count() != Unbounded {
+		t.Errorf("expected=%d, actual=%d", Unbounded, r.Count())
+	}
+	if times := r.Expand(0); times != nil {
+		t.Errorf("expected=nil, actual=%v", times)
+	}
+	times := r.Expand(3)
+	if len(times) != 3 {
+		t.Fatalf("expected=3, actual=%d", len(times))
+	}
+	expected := "2020-04-01T01:00:00+09:00"
+	if actual := times[0].Begin().Format(time.RFC3339); actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+	expected = "2020-04-02T01:00:00+09:00"
+	if actual := times[2].End().Format(time.RFC3339); actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+}
+
+func TestParseRepeating_StartEnd(t *testing.T) {
+	r, err := ParseRepeating("R2/2020-04-01T09:00:00Z/2020-04-01T10:30:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	times := r.Expand(0)
+	if len(times) != 2 {
+		t.Fatalf("expected=2, actual=%d", len(times))
+	}
+	expected := "2020-04-01T12:00:00Z"
+	if actual := times[1].End().Format(time.RFC3339); actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+	if _, err = ParseRepeating("R-1/2020-04-01T09:00:00Z/PT1H"); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestParseRepeating_Count(t *testing.T) {
+	if _, err := ParseRepeating("R999999999999999/2020-04-01T09:00Z/P1D"); err == nil {
+		t.Error("expected error")
+	}
+	r, err := ParseRepeating(fmt.Sprintf("R%d/2020-04-01T09:00Z/PT1S", MaxRepeatCount))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if times := r.Expand(2); len(times) != 2 {
+		t.Errorf("expected=2, actual=%d", len(times))
+	}
+}
@@ -0,0 +1,100 @@
+package gauge
+
+import (
+	"iter"
+	"time"
+
+	"github.com/goccha/times/pkg/civil"
+	"github.com/goccha/times/pkg/zone"
+)
+
+// Instants 開始日時から step 毎の日時を返す(終了日時を含まない、step が0以下の場合は空)
+//
+// step は経過時間として加算するため、夏時間の切り替えを跨ぐと壁時計の時刻がずれる。
+// 壁時計の時刻を揃える場合は InstantsDate を使用する。
+func (t *TimeGauge) Instants(step time.Duration) iter.Seq[time.Time] {
+	return func(yield func(time.Time) bool) {
+		t.EachInstant(step, yield)
+	}
+}
+
+// EachInstant 開始日時から step 毎の日時を順に f に渡す(f が false を返した場合は終了する)
+func (t *TimeGauge) EachInstant(step time.Duration, f func(tm time.Time) bool) {
+	if step <= 0 {
+		return
+	}
+	for tm := t.begin; tm.Before(t.end); tm = tm.Add(step) {
+		if !f(tm) {
+			return
+		}
+	}
+}
+
+// Chunks 期間を開始日時から step 毎に区切った期間を返す(最後の期間は終了日時までとなる、step が0以下の場合は空)
+//
+// step は経過時間として加算する。暦の単位で区切る場合は ChunksDate を使用する。
+func (t *TimeGauge) Chunks(step time.Duration) iter.Seq[*TimeGauge] {
+	return func(yield func(*TimeGauge) bool) {
+		t.EachChunk(step, yield)
+	}
+}
+
+// EachChunk 期間を開始日時から step 毎に区切った期間を順に f に渡す(f が false を返した場合は終了する)
+func (t *TimeGauge) EachChunk(step time.Duration, f func(tg *TimeGauge) bool) {
+	t.EachInstant(step, func(tm time.Time) bool {
+		end := tm.Add(step)
+		if end.After(t.end) {
+			end = t.end
+		}
+		return f(New(tm, end))
+	})
+}
+
+// InstantsDate 開始日時から years 年 months ヶ月 days 日毎の日時を返す(終了日時を含まない)
+//
+// 開始日時のロケーションの壁時計の日付に加算するため、夏時間の切り替えを跨いでも時刻は変わらない。
+// 存在しない・重複する時刻は zone.Compatible で解釈する。
+// 月末を超える日付は time.AddDate と同様に正規化する。いずれかが負、または全て0の場合は空。
+func (t *TimeGauge) InstantsDate(years, months, days int) iter.Seq[time.Time] {
+	return func(yield func(time.Time) bool) {
+		t.eachDate(years, months, days, func(tm, _ time.Time) bool {
+			return yield(tm)
+		})
+	}
+}
+
+// ChunksDate 期間を開始日時から years 年 months ヶ月 days 日毎に区切った期間を返す(最後の期間は終了日時までとなる)
+//
+// 区切りの日時は InstantsDate と同じ。
+func (t *TimeGauge) ChunksDate(years, months, days int) iter.Seq[*TimeGauge] {
+	return func(yield func(*TimeGauge) bool) {
+		t.eachDate(years, months, days, func(tm, next time.Time) bool {
+			if next.After(t.end) {
+				next = t.end
+			}
+			return yield(New(tm, next))
+		})
+	}
+}
+
+// eachDate 暦の単位で区切った日時と次の区切りの日時を順に f に渡す
+func (t *TimeGauge) eachDate(years, months, days int, f func(tm, next time.Time) bool) {
+	if years < 0 || months < 0 || days < 0 || years+months+days == 0 {
+		return
+	}
+	wall, loc := zone.Wall(t.begin), t.begin.Location()
+	tm := t.begin
+	for n := 1; tm.Before(t.end); n++ {
+		// 累積した誤差が出ないよう、常に開始日時の壁時計から加算する
+		next, _ := zone.Resolve(wall.AddDate(years*n, months*n, days*n), loc, zone.Compatible)
+		if !f(tm, next) {
+			return
+		}
+		tm = next
+	}
+}
+
+// All 範囲の日付を順に返す
+func (r DateRange) All() iter.Seq[civil.Date] {
+	return r.Each
+}
@@ -0,0 +1,147 @@
+package gauge
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTimeGauge_Instants(t *testing.T) {
+	begin, _ := time.Parse(time.RFC3339, "2020-04-01T17:00:00+09:00")
+	rec := New(begin, begin.Add(time.Hour))
+	var actual []string
+	for tm := range rec.Instants(15 * time.Minute) {
+		actual = append(actual, tm.Format("15:04"))
+	}
+	expected := []string{"17:00", "17:15", "17:30", "17:45"}
+	if len(actual) != len(expected) {
+		t.Fatalf("expected=%v, actual=%v", expected, actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Errorf("[%d] expected=%s, actual=%s", i, expected[i], actual[i])
+		}
+	}
+	count := 0
+	for range rec.Instants(0) {
+		count++
+	}
+	if count != 0 {
+		t.Errorf("expected=0, actual=%d", count)
+	}
+}
+
+func TestTimeGauge_Chunks(t *testing.T) {
+	begin, _ := time.Parse(time.RFC3339, "2020-04-01T17:00:00+09:00")
+	rec := New(begin, begin.Add(50*time.Minute))
+	var actual []*TimeGauge
+	for tg := range rec.Chunks(20 * time.Minute) {
+		actual = append(actual, tg)
+	}
+	if len(actual) != 3 {
+		t.Fatalf("expected=3, actual=%d", len(actual))
+	}
+	if actual[0].Duration() != 20*time.Minute || actual[2].Duration() != 10*time.Minute || !actual[2].End().Equal(rec.End()) {
+		t.Errorf("unexpected chunks %v", actual)
+	}
+	// 途中で終了する
+	count := 0
+	for range rec.Chunks(time.Minute) {
+		count++
+		if count == 5 {
+			break
+		}
+	}
+	if count != 5 {
+		t.Errorf("expected=5, actual=%d", count)
+	}
+}
+
+func TestTimeGauge_Instants_Large(t *testing.T) {
+	begin, _ := time.Parse(time.RFC3339, "2000-01-01T00:00:00Z")
+	rec := New(begin, begin.AddDate(100, 0, 0))
+	var last time.Time
+	for tm := range rec.Instants(time.Second) {
+		last = tm
+		if tm.Year() == 2000 && tm.Month() == time.February {
+			break
+		}
+	}
+	if last.Format(time.RFC3339) != "2000-02-01T00:00:00Z" {
+		t.Errorf("expected=2000-02-01T00:00:00Z, actual=%s", last.Format(time.RFC3339))
+	}
+}
+
+func TestTimeGauge_InstantsDate(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	// 2020-03-08 に夏時間が始まる
+	begin := time.Date(2020, 3, 6, 9, 0, 0, 0, ny)
+	rec := New(begin, time.Date(2020, 3, 10, 9, 0, 0, 0, ny))
+	var actual []string
+	for tm := range rec.InstantsDate(0, 0, 1) {
+		actual = append(actual, tm.Format(time.RFC3339))
+	}
+	expected := []string{"2020-03-06T09:00:00-05:00", "2020-03-07T09:00:00-05:00", "2020-03-08T09:00:00-04:00", "2020-03-09T09:00:00-04:00"}
+	if len(actual) != len(expected) {
+		t.Fatalf("expected=%v, actual=%v", expected, actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Errorf("[%d] expected=%s, actual=%s", i, expected[i], actual[i])
+		}
+	}
+	// 経過時間で加算すると壁時計の時刻がずれる
+	var last time.Time
+	for tm := range rec.Instants(24 * time.Hour) {
+		last = tm
+	}
+	if actual := last.Format(time.RFC3339); actual != "2020-03-09T10:00:00-04:00" {
+		t.Errorf("expected=2020-03-09T10:00:00-04:00, actual=%s", actual)
+	}
+	// 月末は正規化し、開始日時から加算する
+	begin = time.Date(2020, 1, 31, 0, 0, 0, 0, ny)
+	actual = actual[:0]
+	for tm := range New(begin, begin.AddDate(0, 4, 0)).InstantsDate(0, 1, 0) {
+		actual = append(actual, tm.Format("2006-01-02"))
+	}
+	if s := fmt.Sprint(actual); s != "[2020-01-31 2020-03-02 2020-03-31 2020-05-01]" {
+		t.Errorf("expected=[2020-01-31 2020-03-02 2020-03-31 2020-05-01], actual=%s", s)
+	}
+	for _, step := range [][3]int{{0, 0, 0}, {0, -1, 1}} {
+		for range rec.InstantsDate(step[0], step[1], step[2]) {
+			t.Errorf("%v: expected empty", step)
+		}
+	}
+}
+
+func TestTimeGauge_ChunksDate(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	rec := New(time.Date(2020, 3, 7, 0, 0, 0, 0, ny), time.Date(2020, 3, 9, 12, 0, 0, 0, ny))
+	var actual []time.Duration
+	for tg := range rec.ChunksDate(0, 0, 1) {
+		actual = append(actual, tg.Duration())
+	}
+	expected := []time.Duration{24 * time.Hour, 23 * time.Hour, 12 * time.Hour}
+	if len(actual) != len(expected) {
+		t.Fatalf("expected=%v, actual=%v", expected, actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Errorf("[%d] expected=%v, actual=%v", i, expected[i], actual[i])
+		}
+	}
+}
+
+func TestDateRange_All(t *testing.T) {
+	r := mustDateRange(t, "2020-04-01", "2020-04-30")
+	count := 0
+	for d := range r.All() {
+		count++
+		if d.Day == 10 {
+			break
+		}
+	}
+	if count != 10 {
+		t.Errorf("expected=10, actual=%d", count)
+	}
+}
@@ -0,0 +1,127 @@
+package gauge
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// binaryVersion バイナリ形式のバージョン
+const binaryVersion byte = 1
+
+// jsonGauge JSON のオブジェクト形式
+type jsonGauge struct {
+	Begin *time.Time `json:"begin"`
+	End   *time.Time `json:"end"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// 開始日時・終了日時を RFC 3339 形式で持つオブジェクトに変換する
+func (t TimeGauge) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonGauge{Begin: &t.begin, End: &t.end})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// オブジェクト形式({"begin":...,"end":...})と ISO 8601 の期間文字列形式を受け付ける
+func (t *TimeGauge) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return t.UnmarshalText([]byte(s))
+	}
+	var v jsonGauge
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Begin == nil || v.End == nil {
+		return errors.New("gauge: begin and end are required")
+	}
+	return t.set(*v.Begin, *v.End)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// ISO 8601 形式(<start>/<end>)の期間文字列に変換する
+func (t TimeGauge) MarshalText() ([]byte, error) {
+	return []byte(t.Interval()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// ISO 8601 形式の期間文字列を解析する
+func (t *TimeGauge) UnmarshalText(data []byte) error {
+	tg, err := ParseInterval(string(data))
+	if err != nil {
+		return err
+	}
+	return t.set(tg.begin, tg.end)
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (t TimeGauge) MarshalBinary() ([]byte, error) {
+	begin, err := t.begin.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	end, err := t.end.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 0, 3+len(begin)+len(end))
+	buf = append(buf, binaryVersion, byte(len(begin)))
+	buf = append(buf, begin...)
+	buf = append(buf, byte(len(end)))
+	buf = append(buf, end...)
+	return buf, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (t *TimeGauge) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("gauge: UnmarshalBinary: no data")
+	}
+	if data[0] != binaryVersion {
+		return fmt.Errorf("gauge: UnmarshalBinary: unsupported version %d", data[0])
+	}
+	data = data[1:]
+	var times [2]time.Time
+	for i := range times {
+		if len(data) == 0 || len(data) < int(data[0])+1 {
+			return errors.New("gauge: UnmarshalBinary: invalid length")
+		}
+		n := int(data[0])
+		if err := times[i].UnmarshalBinary(data[1 : n+1]); err != nil {
+			return err
+		}
+		data = data[n+1:]
+	}
+	if len(data) != 0 {
+		return errors.New("gauge: UnmarshalBinary: invalid length")
+	}
+	return t.set(times[0], times[1])
+}
+
+// GobEncode implements the gob.GobEncoder interface.
+func (t TimeGauge) GobEncode() ([]byte, error) {
+	return t.MarshalBinary()
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+func (t *TimeGauge) GobDecode(data []byte) error {
+	return t.UnmarshalBinary(data)
+}
+
+// set 開始日時・終了日時を検証して設定する
+func (t *TimeGauge) set(begin, end time.Time) error {
+	if err := checkOrder(begin, end); err != nil {
+		return err
+	}
+	*t = *New(begin, end)
+	return nil
+}
@@ -0,0 +1,173 @@
+package gauge
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimeGauge_MarshalJSON(t *testing.T) {
+	begin, _ := time.Parse(time.RFC3339, "2020-04-01T17:00:00+09:00")
+	end, _ := time.Parse(time.RFC3339, "2020-04-01T18:00:00-05:00")
+	rec := New(begin, end)
+	data, err := json.Marshal(rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"begin":"2020-04-01T17:00:00+09:00","end":"2020-04-01T18:00:00-05:00"}`
+	if string(data) != expected {
+		t.Errorf("expected=%s, actual=%s", expected, data)
+	}
+	var actual TimeGauge
+	if err = json.Unmarshal(data, &actual); err != nil {
+		t.Fatal(err)
+	}
+	if !actual.Begin().Equal(begin) || !actual.End().Equal(end) {
+		t.Errorf("expected=%v/%v, actual=%v/%v", begin, end, actual.Begin(), actual.End())
+	}
+	if _, offset := actual.End().Zone(); offset != -5*60*60 {
+		t.Errorf("expected=%d, actual=%d", -5*60*60, offset)
+	}
+	if actual.Duration() != 15*time.Hour {
+		t.Errorf("expected=%v, actual=%v", 15*time.Hour, actual.Duration())
+	}
+}
+
+func TestTimeGauge_MarshalValue(t *testing.T) {
+	begin, _ := time.Parse(time.RFC3339, "2020-04-01T17:00:00+09:00")
+	end, _ := time.Parse(time.RFC3339, "2020-04-01T18:00:00+09:00")
+	v := struct {
+		Shift TimeGauge `json:"shift"`
+	}{Shift: *New(begin, end)}
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"shift":{"begin":"2020-04-01T17:00:00+09:00","end":"2020-04-01T18:00:00+09:00"}}`
+	if string(data) != expected {
+		t.Errorf("expected=%s, actual=%s", expected, data)
+	}
+	text, err := New(begin, end).MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := map[string]TimeGauge{"a": *New(begin, end)}
+	if data, err = json.Marshal(m); err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"a":{"begin":"2020-04-01T17:00:00+09:00","end":"2020-04-01T18:00:00+09:00"}}`; string(data) != expected {
+		t.Errorf("expected=%s, actual=%s", expected, data)
+	}
+	var buf bytes.Buffer
+	if err = gob.NewEncoder(&buf).Encode(struct{ G TimeGauge }{*New(begin, end)}); err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct{ G TimeGauge }
+	if err = gob.NewDecoder(&buf).Decode(&decoded); err != nil {
+		t.Fatal(err)
+	}
+	if actual, _ := decoded.G.MarshalText(); string(actual) != string(text) {
+		t.Errorf("expected=%s, actual=%s", text, actual)
+	}
+}
+
+func TestTimeGauge_UnmarshalJSON(t *testing.T) {
+	var actual struct {
+		Shift TimeGauge `json:"shift"`
+	}
+	data := `{"shift":"2020-04-01T17:00:00+09:00/PT8H"}`
+	if err := json.Unmarshal([]byte(data), &actual); err != nil {
+		t.Fatal(err)
+	}
+	expected := "2020-04-02T01:00:00+09:00"
+	if s := actual.Shift.End().Format(time.RFC3339); s != expected {
+		t.Errorf("expected=%s, actual=%s", expected, s)
+	}
+
+	for _, s := range []string{
+		`{"begin":"2020-04-01T18:00:00+09:00","end":"2020-04-01T17:00:00+09:00"}`,
+		`{"begin":"2020-04-01T18:00:00+09:00"}`,
+		`{"begin":"2020-04-01","end":"2020-04-02"}`,
+		`"2020-04-01T18:00:00+09:00/2020-04-01T17:00:00+09:00"`,
+		`"2020-04-01T18:00:00+09:00"`,
+		`[]`,
+	} {
+		var tg TimeGauge
+		if err := json.Unmarshal([]byte(s), &tg); err == nil {
+			t.Errorf("%s: expected error", s)
+		}
+	}
+}
+
+func TestTimeGauge_MarshalText(t *testing.T) {
+	begin, _ := time.Parse(time.RFC3339, "2020-04-01T17:00:00+09:00")
+	end, _ := time.Parse(time.RFC3339, "2020-04-01T18:00:00+09:00")
+	m := map[string]*TimeGauge{"a": New(begin, end)}
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"a":{"begin":"2020-04-01T17:00:00+09:00","end":"2020-04-01T18:00:00+09:00"}}`
+	if string(data) != expected {
+		t.Errorf("expected=%s, actual=%s", expected, data)
+	}
+	text, _ := New(begin, end).MarshalText()
+	expected = "2020-04-01T17:00:00+09:00/2020-04-01T18:00:00+09:00"
+	if string(text) != expected {
+		t.Errorf("expected=%s, actual=%s", expected, text)
+	}
+	var actual TimeGauge
+	if err = actual.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+	if !actual.Begin().Equal(begin) || !actual.End().Equal(end) {
+		t.Errorf("expected=%v/%v, actual=%v/%v", begin, end, actual.Begin(), actual.End())
+	}
+}
+
+func TestTimeGauge_MarshalBinary(t *testing.T) {
+	begin, _ := time.Parse(time.RFC3339Nano, "2020-04-01T17:00:00.123456789+09:00")
+	end, _ := time.Parse(time.RFC3339, "2020-04-01T18:00:00+05:30")
+	rec := New(begin, end)
+	data, err := rec.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actual TimeGauge
+	if err = actual.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !actual.Begin().Equal(begin) || !actual.End().Equal(end) {
+		t.Errorf("expected=%v/%v, actual=%v/%v", begin, end, actual.Begin(), actual.End())
+	}
+	if actual.End().Format(time.RFC3339) != "2020-04-01T18:00:00+05:30" {
+		t.Errorf("expected=+05:30, actual=%s", actual.End().Format(time.RFC3339))
+	}
+	for _, d := range [][]byte{nil, {2}, data[:len(data)-1], append(append([]byte{}, data...), 0)} {
+		if err = actual.UnmarshalBinary(d); err == nil {
+			t.Errorf("%v: expected error", d)
+		}
+	}
+	inverted, _ := New(end, begin).MarshalBinary()
+	if err = actual.UnmarshalBinary(inverted); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestTimeGauge_Gob(t *testing.T) {
+	begin, _ := time.Parse(time.RFC3339, "2020-04-01T17:00:00+09:00")
+	end, _ := time.Parse(time.RFC3339, "2020-04-01T18:00:00+09:00")
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(New(begin, end)); err != nil {
+		t.Fatal(err)
+	}
+	var actual TimeGauge
+	if err := gob.NewDecoder(&buf).Decode(&actual); err != nil {
+		t.Fatal(err)
+	}
+	if actual.Duration() != time.Hour {
+		t.Errorf("expected=%v, actual=%v", time.Hour, actual.Duration())
+	}
+}
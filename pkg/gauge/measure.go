@@ -0,0 +1,68 @@
+package gauge
+
+import (
+	"sync/atomic"
+
+	"github.com/goccha/times/pkg/clock"
+)
+
+// Tracker 処理時間を計測して Sink に出力する
+//
+// ゼロ値はシステム時計を使用し、出力を行わない Tracker として使用できる。
+type Tracker struct {
+	Sink  Sink        // 出力先(nil の場合は出力しない)
+	Clock clock.Clock // 時計(nil の場合はシステム時計)
+}
+
+// Measure 関数の処理時間を計測する(関数が panic した場合も計測結果を出力する)
+func (t *Tracker) Measure(name string, f func()) (tg *TimeGauge) {
+	done := t.Track(name)
+	defer func() {
+		tg = done()
+	}()
+	f()
+	return
+}
+
+// Track 計測を開始し、計測を終了する関数を返す
+//
+//	defer tracker.Track("name")()
+func (t *Tracker) Track(name string) func() *TimeGauge {
+	c := clock.OrNew(t.Clock)
+	begin := c.Now()
+	return func() *TimeGauge {
+		tg := New(begin, c.Now())
+		if t.Sink != nil {
+			t.Sink.Record(name, tg)
+		}
+		return tg
+	}
+}
+
+var defaultTracker atomic.Pointer[Tracker]
+
+func init() {
+	defaultTracker.Store(&Tracker{})
+}
+
+// SetDefaultSink Measure・Track の出力先を設定する
+func SetDefaultSink(s Sink) {
+	defaultTracker.Store(&Tracker{Sink: s})
+}
+
+// DefaultTracker Measure・Track で使用する Tracker を返す
+func DefaultTracker() *Tracker {
+	return defaultTracker.Load()
+}
+
+// Measure 関数の処理時間を計測して既定の出力先に出力する
+func Measure(name string, f func()) *TimeGauge {
+	return DefaultTracker().Measure(name, f)
+}
+
+// Track 計測を開始し、計測を終了して既定の出力先に出力する関数を返す
+//
+//	defer gauge.Track("name")()
+func Track(name string) func() *TimeGauge {
+	return DefaultTracker().Track(name)
+}
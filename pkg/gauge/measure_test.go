@@ -0,0 +1,70 @@
+package gauge
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goccha/times/pkg/clock"
+)
+
+func TestTracker_Measure(t *testing.T) {
+	begin, _ := time.Parse(time.RFC3339, "2020-04-01T17:00:00+09:00")
+	c := clock.NewFake(begin)
+	sink := NewMemorySink()
+	tracker := &Tracker{Sink: sink, Clock: c}
+	tg := tracker.Measure("job", func() {
+		c.Advance(3 * time.Second)
+	})
+	if tg.Duration() != 3*time.Second {
+		t.Errorf("expected=%v, actual=%v", 3*time.Second, tg.Duration())
+	}
+	func() {
+		defer tracker.Track("step")()
+		c.Advance(time.Second)
+	}()
+	func() {
+		defer func() {
+			_ = recover()
+		}()
+		tracker.Measure("job", func() {
+			c.Advance(2 * time.Second)
+			panic("failed")
+		})
+	}()
+	records := sink.Records()
+	if len(records) != 3 {
+		t.Fatalf("expected=3, actual=%d", len(records))
+	}
+	if records[1].Name != "step" || records[1].Gauge.Duration() != time.Second {
+		t.Errorf("expected=step 1s, actual=%s %v", records[1].Name, records[1].Gauge.Duration())
+	}
+	jobs := sink.Gauges("job")
+	if len(jobs) != 2 || jobs[1].Duration() != 2*time.Second {
+		t.Errorf("expected=2 jobs, actual=%v", jobs)
+	}
+	sink.Reset()
+	if len(sink.Records()) != 0 {
+		t.Error("expected empty sink")
+	}
+}
+
+func TestTracker_Zero(t *testing.T) {
+	var tracker Tracker
+	tg := tracker.Measure("noop", func() {})
+	if tg.Duration() < 0 {
+		t.Errorf("expected>=0, actual=%v", tg.Duration())
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	var names []string
+	SetDefaultSink(MultiSink(SinkFunc(func(name string, tg *TimeGauge) {
+		names = append(names, name)
+	}), NewSlogSink(nil, -8)))
+	defer SetDefaultSink(nil)
+	Measure("a", func() {})
+	Track("b")()
+	if len(names) != 2 || names[0] != "a" || names[1] != "b" {
+		t.Errorf("expected=[a b], actual=%v", names)
+	}
+}
@@ -0,0 +1,159 @@
+package gauge
+
+import (
+	"errors"
+	"time"
+
+	"github.com/goccha/times/pkg/civil"
+	"github.com/goccha/times/pkg/clock"
+)
+
+// ErrUnbounded 開始日時が未確定で評価できない
+var ErrUnbounded = errors.New("gauge: begin is unknown")
+
+// OpenGauge 終了日時(または開始日時)が未確定の期間
+//
+// 未確定の終了日時は評価時点(WithHorizon で指定した日時、無ければ WithClock で指定した時計の現在時刻)とし、
+// 未確定の開始日時は WithOrigin で指定した日時(無ければ下限なし)として評価する。
+// Close で未確定の日時を確定すると、通常の TimeGauge を返す。
+type OpenGauge struct {
+	begin   time.Time
+	end     time.Time
+	clock   clock.Clock
+	horizon time.Time
+	origin  time.Time
+}
+
+// OpenOption 未確定の期間の評価方法を指定する(未確定の終了日時の評価に使用する時計は WithClock で指定する)
+type OpenOption interface {
+	applyOpen(o *OpenGauge)
+}
+
+type openOption func(o *OpenGauge)
+
+func (f openOption) applyOpen(o *OpenGauge) {
+	f(o)
+}
+
+// WithHorizon 未確定の終了日時を評価する日時を指定する(時計より優先する)
+func WithHorizon(t time.Time) OpenOption {
+	return openOption(func(o *OpenGauge) {
+		o.horizon = t
+	})
+}
+
+// WithOrigin 未確定の開始日時を評価する日時を指定する
+func WithOrigin(t time.Time) OpenOption {
+	return openOption(func(o *OpenGauge) {
+		o.origin = t
+	})
+}
+
+// Open 開始日時のみが確定している(継続中の)期間を生成する
+func Open(begin time.Time, opts ...OpenOption) *OpenGauge {
+	return newOpenGauge(begin, time.Time{}, opts)
+}
+
+// OpenUntil 終了日時のみが確定している期間を生成する
+func OpenUntil(end time.Time, opts ...OpenOption) *OpenGauge {
+	return newOpenGauge(time.Time{}, end, opts)
+}
+
+func newOpenGauge(begin, end time.Time, opts []OpenOption) *OpenGauge {
+	o := &OpenGauge{begin: begin, end: end, clock: clock.New()}
+	for _, opt := range opts {
+		opt.applyOpen(o)
+	}
+	return o
+}
+
+// Begin 開始日時を返す(未確定の場合は false)
+func (o *OpenGauge) Begin() (time.Time, bool) {
+	return o.begin, !o.begin.IsZero()
+}
+
+// End 終了日時を返す(未確定の場合は false)
+func (o *OpenGauge) End() (time.Time, bool) {
+	return o.end, !o.end.IsZero()
+}
+
+// Now 未確定の終了日時を評価する日時を返す
+func (o *OpenGauge) Now() time.Time {
+	if !o.horizon.IsZero() {
+		return o.horizon
+	}
+	return o.clock.Now()
+}
+
+// bounds 評価時点での開始日時・終了日時を返す(開始日時が下限なしの場合はゼロ値)
+func (o *OpenGauge) bounds() (time.Time, time.Time) {
+	begin, end := o.begin, o.end
+	if begin.IsZero() {
+		begin = o.origin
+	}
+	if end.IsZero() {
+		end = o.Now()
+		if !begin.IsZero() && end.Before(begin) {
+			end = begin // 評価時点が開始日時より前の場合は長さ0とする
+		}
+	}
+	return begin, end
+}
+
+// Snapshot 評価時点で閉じた期間を返す(開始日時が評価できない場合は ErrUnbounded)
+func (o *OpenGauge) Snapshot() (*TimeGauge, error) {
+	begin, end := o.bounds()
+	if begin.IsZero() {
+		return nil, ErrUnbounded
+	}
+	return NewChecked(begin, end, AllowZero(), AllowMixedLocations())
+}
+
+// Duration 評価時点での期間を返す(評価できない場合は0)
+func (o *OpenGauge) Duration() time.Duration {
+	tg, err := o.Snapshot()
+	if err != nil {
+		return 0
+	}
+	return tg.Duration()
+}
+
+// Split 評価時点で閉じた期間を基準時刻で分割する(評価できない場合は空のスライス)
+func (o *OpenGauge) Split(hour, min, sec, ns int, loc *time.Location, opts ...SplitOption) []TimeGauge {
+	tg, err := o.Snapshot()
+	if err != nil {
+		return make([]TimeGauge, 0)
+	}
+	return tg.Split(hour, min, sec, ns, loc, opts...)
+}
+
+// SplitAt 評価時点で閉じた期間を基準時刻で分割する
+func (o *OpenGauge) SplitAt(tod civil.TimeOfDay, loc *time.Location, opts ...SplitOption) ([]TimeGauge, error) {
+	tg, err := o.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	return tg.SplitAt(tod, loc, opts...)
+}
+
+// Overlap 評価時点で指定した期間と重複しているかどうか(開始日時が評価できない場合は下限なし)
+func (o *OpenGauge) Overlap(start time.Time, end time.Time) bool {
+	begin, until := o.bounds()
+	tg := makeGauge("", begin, until)
+	return tg.Overlap(start, end)
+}
+
+// Contains 評価時点で指定した日時が期間内に含まれるかどうか(開始日時が評価できない場合は下限なし)
+func (o *OpenGauge) Contains(tm time.Time) bool {
+	begin, end := o.bounds()
+	tg := makeGauge("", begin, end)
+	return tg.Contains(tm)
+}
+
+// Close 未確定の日時を確定した期間を返す(OpenGauge は変更しない)
+func (o *OpenGauge) Close(t time.Time) (*TimeGauge, error) {
+	if o.end.IsZero() {
+		return NewChecked(o.begin, t, AllowZero(), AllowMixedLocations())
+	}
+	return NewChecked(t, o.end, AllowZero(), AllowMixedLocations())
+}
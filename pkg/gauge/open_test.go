@@ -0,0 +1,85 @@
+package gauge
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/goccha/times/pkg/clock"
+)
+
+var (
+	_ StopwatchOption = ClockOption{}
+	_ OpenOption      = ClockOption{}
+)
+
+func TestOpenGauge(t *testing.T) {
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	begin := time.Date(2020, 4, 1, 22, 0, 0, 0, tokyo)
+	c := clock.NewFake(begin.Add(3 * time.Hour))
+	o := Open(begin, WithClock(c))
+	if _, ok := o.End(); ok {
+		t.Error("expected open end")
+	}
+	if d := o.Duration(); d != 3*time.Hour {
+		t.Errorf("expected=%v, actual=%v", 3*time.Hour, d)
+	}
+	c.Advance(5 * time.Hour)
+	if d := o.Duration(); d != 8*time.Hour {
+		t.Errorf("expected=%v, actual=%v", 8*time.Hour, d)
+	}
+	segments := o.Split(5, 0, 0, 0, tokyo)
+	if len(segments) != 2 || segments[0].Hours() != 7 || segments[1].Hours() != 1 {
+		t.Errorf("unexpected segments %v", segments)
+	}
+	if !o.Contains(begin.Add(7*time.Hour)) || o.Contains(begin.Add(9*time.Hour)) {
+		t.Error("unexpected Contains")
+	}
+	if !o.Overlap(begin.Add(7*time.Hour), begin.Add(10*time.Hour)) || o.Overlap(begin.Add(8*time.Hour), begin.Add(10*time.Hour)) {
+		t.Error("unexpected Overlap")
+	}
+
+	// 評価日時の指定は時計より優先する
+	o = Open(begin, WithClock(c), WithHorizon(begin.Add(time.Hour)))
+	if d := o.Duration(); d != time.Hour {
+		t.Errorf("expected=%v, actual=%v", time.Hour, d)
+	}
+	// 評価日時が開始日時より前の場合は長さ0
+	o = Open(begin, WithHorizon(begin.Add(-time.Hour)))
+	if d := o.Duration(); d != 0 {
+		t.Errorf("expected=0, actual=%v", d)
+	}
+}
+
+func TestOpenGauge_Close(t *testing.T) {
+	begin := time.Date(2020, 4, 1, 9, 0, 0, 0, time.UTC)
+	o := Open(begin)
+	tg, err := o.Close(begin.Add(9 * time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tg.Hours() != 9 || !tg.Begin().Equal(begin) {
+		t.Errorf("unexpected gauge %s", tg.Interval())
+	}
+	if _, ok := o.End(); ok {
+		t.Error("Close must not modify OpenGauge")
+	}
+	if _, err := o.Close(begin.Add(-time.Hour)); !errors.Is(err, ErrInverted) {
+		t.Errorf("expected=%v, actual=%v", ErrInverted, err)
+	}
+
+	end := begin.Add(9 * time.Hour)
+	o = OpenUntil(end)
+	if _, err := o.Snapshot(); !errors.Is(err, ErrUnbounded) {
+		t.Errorf("expected=%v, actual=%v", ErrUnbounded, err)
+	}
+	if o.Duration() != 0 || !o.Contains(begin) || !o.Overlap(begin.AddDate(-1, 0, 0), begin) {
+		t.Error("unexpected unbounded evaluation")
+	}
+	if tg, err := OpenUntil(end, WithOrigin(begin)).Snapshot(); err != nil || tg.Hours() != 9 {
+		t.Errorf("unexpected snapshot %v %v", tg, err)
+	}
+	if tg, err := o.Close(begin); err != nil || tg.Hours() != 9 {
+		t.Errorf("unexpected gauge %v %v", tg, err)
+	}
+}
@@ -0,0 +1,112 @@
+package gauge
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// Sink 計測結果の出力先
+type Sink interface {
+	// Record 計測結果を出力する
+	Record(name string, tg *TimeGauge)
+}
+
+// SinkFunc 関数を Sink として使用する
+type SinkFunc func(name string, tg *TimeGauge)
+
+// Record 計測結果を関数に渡す
+func (f SinkFunc) Record(name string, tg *TimeGauge) {
+	f(name, tg)
+}
+
+// MultiSink 複数の Sink に出力する Sink を返す
+func MultiSink(sinks ...Sink) Sink {
+	return multiSink(append([]Sink(nil), sinks...))
+}
+
+type multiSink []Sink
+
+func (m multiSink) Record(name string, tg *TimeGauge) {
+	for _, s := range m {
+		s.Record(name, tg)
+	}
+}
+
+// Record 計測結果
+type Record struct {
+	Name  string
+	Gauge *TimeGauge
+}
+
+// MemorySink 計測結果をメモリに保持する Sink
+type MemorySink struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+// NewMemorySink 計測結果をメモリに保持する Sink を生成する
+func NewMemorySink() *MemorySink {
+	return &MemorySink{}
+}
+
+// Record 計測結果を保持する
+func (s *MemorySink) Record(name string, tg *TimeGauge) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, Record{Name: name, Gauge: tg})
+}
+
+// Records 保持している計測結果を記録順に返す
+func (s *MemorySink) Records() []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records := make([]Record, len(s.records))
+	copy(records, s.records)
+	return records
+}
+
+// Gauges 指定した名前の計測結果を記録順に返す
+func (s *MemorySink) Gauges(name string) []*TimeGauge {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var times []*TimeGauge
+	for _, r := range s.records {
+		if r.Name == name {
+			times = append(times, r.Gauge)
+		}
+	}
+	return times
+}
+
+// Reset 保持している計測結果を破棄する
+func (s *MemorySink) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = nil
+}
+
+// SlogSink 計測結果を log/slog に出力する Sink
+type SlogSink struct {
+	logger *slog.Logger
+	level  slog.Level
+}
+
+// NewSlogSink 計測結果を指定したレベルでログ出力する Sink を生成する(logger が nil の場合は slog.Default())
+func NewSlogSink(logger *slog.Logger, level slog.Level) *SlogSink {
+	return &SlogSink{logger: logger, level: level}
+}
+
+// Record 計測結果をログ出力する
+func (s *SlogSink) Record(name string, tg *TimeGauge) {
+	logger := s.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.LogAttrs(context.Background(), s.level, "gauge",
+		slog.String("name", name),
+		slog.Time("begin", tg.Begin()),
+		slog.Time("end", tg.End()),
+		slog.Duration("duration", tg.Duration()),
+	)
+}
@@ -0,0 +1,37 @@
+package gauge
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSlogSink(t *testing.T) {
+	begin, _ := time.Parse(time.RFC3339, "2020-04-01T17:00:00+09:00")
+	end, _ := time.Parse(time.RFC3339, "2020-04-01T17:00:01.5+09:00")
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	NewSlogSink(logger, slog.LevelInfo).Record("job", New(begin, end))
+	actual := buf.String()
+	for _, expected := range []string{"msg=gauge", "name=job", "duration=1.5s", "begin=2020-04-01T17:00:00.000+09:00"} {
+		if !strings.Contains(actual, expected) {
+			t.Errorf("expected=%s, actual=%s", expected, actual)
+		}
+	}
+	buf.Reset()
+	NewSlogSink(logger, slog.LevelDebug).Record("job", New(begin, end))
+	if buf.Len() != 0 {
+		t.Errorf("expected=empty, actual=%s", buf.String())
+	}
+}
+
+func TestMultiSink(t *testing.T) {
+	a, b := NewMemorySink(), NewMemorySink()
+	sink := MultiSink(a, b)
+	sink.Record("x", New(time.Now(), time.Now()))
+	if len(a.Records()) != 1 || len(b.Records()) != 1 {
+		t.Errorf("expected=1,1, actual=%d,%d", len(a.Records()), len(b.Records()))
+	}
+}
@@ -0,0 +1,194 @@
+package gauge
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// rangeResolution PostgreSQL の timestamptz の分解能
+const rangeResolution = time.Microsecond
+
+// ErrUnboundedRange 下限・上限が共にない範囲((,))は TimeGauge で表現できない
+var ErrUnboundedRange = errors.New("gauge: both bounds are unbounded")
+
+var rangeLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07",
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999-07:00:00",
+	"2006-01-02T15:04:05.999999999-07",
+	"2006-01-02T15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999-07:00:00",
+}
+
+// Value implements the driver.Valuer interface.
+//
+// PostgreSQL の tstzrange のリテラル(["2020-04-01 17:00:00+09","2020-04-01 18:00:00+09"))に変換する。
+// 開始日時・終了日時がゼロ値の場合は下限・上限なし、両方ゼロ値の場合は empty とする。
+// 両方ゼロ値の期間は empty を表すため、下限・上限が共にない範囲((,))は出力できず、Scan も ErrUnboundedRange を返す。
+// 値レシーバのため、nil の *TimeGauge は database/sql により NULL に変換される。
+func (t TimeGauge) Value() (driver.Value, error) {
+	if t.IsZero() {
+		return "empty", nil
+	}
+	if !t.begin.IsZero() && !t.end.IsZero() {
+		if err := checkOrder(t.begin, t.end); err != nil {
+			return nil, err
+		}
+	}
+	var sb strings.Builder
+	if t.begin.IsZero() {
+		sb.WriteByte('(')
+	} else {
+		sb.WriteString(`["`)
+		sb.WriteString(formatRangeTime(t.begin))
+		sb.WriteByte('"')
+	}
+	sb.WriteByte(',')
+	if !t.end.IsZero() {
+		sb.WriteByte('"')
+		sb.WriteString(formatRangeTime(t.end))
+		sb.WriteByte('"')
+	}
+	sb.WriteByte(')')
+	return sb.String(), nil
+}
+
+// Scan implements the sql.Scanner interface.
+//
+// PostgreSQL の tstzrange のリテラルを解析する。
+// 下限が排他的な場合、上限が包括的な場合は timestamptz の分解能(1マイクロ秒)で半開区間に変換する。
+// 下限・上限なし(infinity を含む)はゼロ値、empty は開始日時・終了日時が共にゼロ値の期間になる。
+// 下限・上限が共にない範囲は empty と区別できないため ErrUnboundedRange を返す。
+func (t *TimeGauge) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*t = TimeGauge{}
+		return nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("gauge: cannot scan %T into TimeGauge", src)
+	}
+	begin, end, err := parseRange(s)
+	if err != nil {
+		return err
+	}
+	if begin.IsZero() && end.IsZero() {
+		if strings.EqualFold(strings.TrimSpace(s), "empty") {
+			*t = TimeGauge{}
+			return nil
+		}
+		return fmt.Errorf("gauge: unsupported range %q: %w", s, ErrUnboundedRange)
+	}
+	if !begin.IsZero() && !end.IsZero() && end.Before(begin) {
+		return fmt.Errorf("gauge: invalid range %q: %w", s, ErrInverted)
+	}
+	*t = *New(begin, end)
+	return nil
+}
+
+// IsZero 開始日時・終了日時が共にゼロ値かどうか
+func (t *TimeGauge) IsZero() bool {
+	return t.begin.IsZero() && t.end.IsZero()
+}
+
+func formatRangeTime(t time.Time) string {
+	layout := "2006-01-02 15:04:05.999999-07"
+	_, offset := t.Zone()
+	if offset%3600 != 0 {
+		layout += ":00"
+	}
+	return t.Round(rangeResolution).Format(layout)
+}
+
+// parseRange tstzrange のリテラルを解析して半開区間の開始日時・終了日時を返す
+func parseRange(s string) (begin, end time.Time, err error) {
+	orig := s
+	s = strings.TrimSpace(s)
+	if strings.EqualFold(s, "empty") {
+		return time.Time{}, time.Time{}, nil
+	}
+	if len(s) < 3 {
+		return time.Time{}, time.Time{}, fmt.Errorf("gauge: invalid range %q", orig)
+	}
+	lower, upper := s[0], s[len(s)-1]
+	if lower != '[' && lower != '(' || upper != ']' && upper != ')' {
+		return time.Time{}, time.Time{}, fmt.Errorf("gauge: invalid range %q", orig)
+	}
+	lo, rest, err := readRangeBound(s[1 : len(s)-1])
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("gauge: invalid range %q: %w", orig, err)
+	}
+	if !strings.HasPrefix(rest, ",") {
+		return time.Time{}, time.Time{}, fmt.Errorf("gauge: invalid range %q", orig)
+	}
+	hi, rest, err := readRangeBound(rest[1:])
+	if err != nil || rest != "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("gauge: invalid range %q", orig)
+	}
+	if begin, err = parseRangeTime(lo); err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("gauge: invalid range %q: %w", orig, err)
+	}
+	if end, err = parseRangeTime(hi); err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("gauge: invalid range %q: %w", orig, err)
+	}
+	if lower == '(' && !begin.IsZero() {
+		begin = begin.Add(rangeResolution)
+	}
+	if upper == ']' && !end.IsZero() {
+		end = end.Add(rangeResolution)
+	}
+	return begin, end, nil
+}
+
+// readRangeBound 引用符付き・引用符なしの境界値を読み取る
+func readRangeBound(s string) (string, string, error) {
+	if !strings.HasPrefix(s, `"`) {
+		i := strings.IndexByte(s, ',')
+		if i < 0 {
+			i = len(s)
+		}
+		return strings.TrimSpace(s[:i]), s[i:], nil
+	}
+	var sb strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				sb.WriteByte(s[i])
+			}
+		case '"':
+			if i+1 < len(s) && s[i+1] == '"' {
+				i++
+				sb.WriteByte('"')
+				continue
+			}
+			return sb.String(), s[i+1:], nil
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", "", errors.New("unterminated quote")
+}
+
+func parseRangeTime(s string) (time.Time, error) {
+	switch strings.ToLower(s) {
+	case "", "infinity", "-infinity":
+		return time.Time{}, nil
+	}
+	var err error
+	for _, layout := range rangeLayouts {
+		var t time.Time
+		if t, err = time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
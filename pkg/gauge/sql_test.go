@@ -0,0 +1,110 @@
+package gauge
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+)
+
+var (
+	_ driver.Valuer = TimeGauge{}
+	_ driver.Valuer = (*TimeGauge)(nil)
+	_ sql.Scanner   = (*TimeGauge)(nil)
+)
+
+func TestTimeGauge_Value(t *testing.T) {
+	begin, _ := time.Parse(time.RFC3339, "2020-04-01T17:00:00+09:00")
+	end, _ := time.Parse(time.RFC3339, "2020-04-01T18:00:00+09:00")
+	tests := []struct {
+		gauge    *TimeGauge
+		expected string
+	}{
+		{New(begin, end), `["2020-04-01 17:00:00+09","2020-04-01 18:00:00+09")`},
+		{New(begin.UTC(), end.In(time.FixedZone("IST", 19800))), `["2020-04-01 08:00:00+00","2020-04-01 14:30:00+05:30")`},
+		{New(begin.Add(123456*time.Microsecond), end), `["2020-04-01 17:00:00.123456+09","2020-04-01 18:00:00+09")`},
+		{New(begin, time.Time{}), `["2020-04-01 17:00:00+09",)`},
+		{New(time.Time{}, end), `(,"2020-04-01 18:00:00+09")`},
+		{&TimeGauge{}, `empty`},
+	}
+	for _, tt := range tests {
+		v, err := tt.gauge.Value()
+		if err != nil {
+			t.Errorf("%s: %v", tt.expected, err)
+			continue
+		}
+		if v != tt.expected {
+			t.Errorf("expected=%s, actual=%v", tt.expected, v)
+		}
+		var actual TimeGauge
+		if err = actual.Scan(v); err != nil {
+			t.Errorf("%s: %v", tt.expected, err)
+			continue
+		}
+		if !actual.Begin().Equal(tt.gauge.Begin()) || !actual.End().Equal(tt.gauge.End()) {
+			t.Errorf("%s: round trip mismatch %v/%v", tt.expected, actual.Begin(), actual.End())
+		}
+	}
+	if _, err := New(end, begin).Value(); err == nil {
+		t.Error("expected error")
+	}
+	// nil のポインタは NULL、値は driver.Valuer として変換される
+	if v, err := driver.DefaultParameterConverter.ConvertValue((*TimeGauge)(nil)); err != nil || v != nil {
+		t.Errorf("expected=<nil>, actual=%v (%v)", v, err)
+	}
+	if v, err := driver.DefaultParameterConverter.ConvertValue(*New(begin, end)); err != nil || v != `["2020-04-01 17:00:00+09","2020-04-01 18:00:00+09")` {
+		t.Errorf("expected=%s, actual=%v (%v)", `["2020-04-01 17:00:00+09","2020-04-01 18:00:00+09")`, v, err)
+	}
+}
+
+func TestTimeGauge_Scan(t *testing.T) {
+	begin, _ := time.Parse(time.RFC3339, "2020-04-01T17:00:00+09:00")
+	end, _ := time.Parse(time.RFC3339, "2020-04-01T18:00:00+09:00")
+	tests := []struct {
+		src   interface{}
+		begin time.Time
+		end   time.Time
+	}{
+		{`["2020-04-01 17:00:00+09","2020-04-01 18:00:00+09")`, begin, end},
+		{[]byte(`["2020-04-01 08:00:00+00","2020-04-01 09:00:00+00")`), begin, end},
+		{`("2020-04-01 17:00:00+09","2020-04-01 18:00:00+09"]`, begin.Add(time.Microsecond), end.Add(time.Microsecond)},
+		{`[2020-04-01T17:00:00+09:00,2020-04-01T18:00:00+09:00)`, begin, end},
+		{`["2020-04-01 17:00:00+09",infinity)`, begin, time.Time{}},
+		{`(-infinity,"2020-04-01 18:00:00+09")`, time.Time{}, end},
+		{`empty`, time.Time{}, time.Time{}},
+		{nil, time.Time{}, time.Time{}},
+	}
+	for _, tt := range tests {
+		var actual TimeGauge
+		if err := actual.Scan(tt.src); err != nil {
+			t.Errorf("%v: %v", tt.src, err)
+			continue
+		}
+		if !actual.Begin().Equal(tt.begin) || !actual.End().Equal(tt.end) {
+			t.Errorf("%v: expected=%v/%v, actual=%v/%v", tt.src, tt.begin, tt.end, actual.Begin(), actual.End())
+		}
+	}
+
+	// 下限・上限が共にない範囲は empty と区別できない
+	for _, src := range []string{`(,)`, `(-infinity,infinity)`, `[,]`} {
+		var actual TimeGauge
+		if err := actual.Scan(src); !errors.Is(err, ErrUnboundedRange) {
+			t.Errorf("%s: expected=%v, actual=%v", src, ErrUnboundedRange, err)
+		}
+	}
+
+	for _, src := range []interface{}{
+		`["2020-04-01 18:00:00+09","2020-04-01 17:00:00+09")`,
+		`["2020-04-01 17:00:00+09","2020-04-01 18:00:00+09"`,
+		`["2020-04-01 17:00:00+09,"2020-04-01 18:00:00+09")`,
+		`[2020-04-01,2020-04-02)`,
+		`[a,b,c)`,
+		123,
+	} {
+		var actual TimeGauge
+		if err := actual.Scan(src); err == nil {
+			t.Errorf("%v: expected error", src)
+		}
+	}
+}
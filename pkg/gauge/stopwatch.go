@@ -0,0 +1,249 @@
+package gauge
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/goccha/times/pkg/clock"
+)
+
+var (
+	// ErrStopwatchStarted ストップウォッチが既に開始されている
+	ErrStopwatchStarted = errors.New("gauge: stopwatch already started")
+	// ErrStopwatchNotRunning ストップウォッチが計測中ではない
+	ErrStopwatchNotRunning = errors.New("gauge: stopwatch is not running")
+	// ErrStopwatchNotPaused ストップウォッチが一時停止中ではない
+	ErrStopwatchNotPaused = errors.New("gauge: stopwatch is not paused")
+)
+
+type stopwatchState int
+
+const (
+	stopwatchIdle stopwatchState = iota
+	stopwatchRunning
+	stopwatchPaused
+	stopwatchStopped
+)
+
+// StopwatchOption ストップウォッチのオプション
+type StopwatchOption interface {
+	applyStopwatch(s *Stopwatch)
+}
+
+// ClockOption 使用する時計を指定するオプション(StopwatchOption, OpenOption として使用できる)
+type ClockOption struct {
+	clock clock.Clock
+}
+
+// WithClock 使用する時計を指定する(nil の場合はシステム時計)
+func WithClock(c clock.Clock) ClockOption {
+	return ClockOption{clock: c}
+}
+
+func (o ClockOption) applyStopwatch(s *Stopwatch) {
+	s.clock = clock.OrNew(o.clock)
+}
+
+func (o ClockOption) applyOpen(g *OpenGauge) {
+	g.clock = clock.OrNew(o.clock)
+}
+
+// Stopwatch 時間計測を行うストップウォッチ
+//
+// 計測結果は TimeGauge として返す。システム時計を使用する場合、経過時間はモノトニック時計で計測される。
+// 複数の goroutine から同時に使用できる。
+type Stopwatch struct {
+	mu        sync.Mutex
+	clock     clock.Clock
+	state     stopwatchState
+	begin     time.Time
+	end       time.Time
+	lapBegin  time.Time
+	laps      []*TimeGauge
+	pausedAt  time.Time
+	paused    time.Duration
+	lapPaused time.Duration
+	lapActive []time.Duration
+}
+
+// NewStopwatch ストップウォッチを生成する
+func NewStopwatch(opts ...StopwatchOption) *Stopwatch {
+	s := &Stopwatch{clock: clock.New()}
+	for _, opt := range opts {
+		opt.applyStopwatch(s)
+	}
+	return s
+}
+
+// StartStopwatch ストップウォッチを生成して計測を開始する
+func StartStopwatch(opts ...StopwatchOption) *Stopwatch {
+	s := NewStopwatch(opts...)
+	_ = s.Start()
+	return s
+}
+
+// Start 計測を開始する
+func (s *Stopwatch) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != stopwatchIdle {
+		return ErrStopwatchStarted
+	}
+	now := s.clock.Now()
+	s.state = stopwatchRunning
+	s.begin = now
+	s.lapBegin = now
+	return nil
+}
+
+// Stop 計測を終了し、計測全体の期間を返す
+//
+// 最後のラップ以降の期間は最終ラップとして記録される。
+func (s *Stopwatch) Stop() (*TimeGauge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != stopwatchRunning && s.state != stopwatchPaused {
+		return nil, ErrStopwatchNotRunning
+	}
+	now := s.clock.Now()
+	s.resume(now)
+	s.lap(now)
+	s.state = stopwatchStopped
+	s.end = now
+	return New(s.begin, s.end), nil
+}
+
+// Pause 計測を一時停止する
+func (s *Stopwatch) Pause() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != stopwatchRunning {
+		return ErrStopwatchNotRunning
+	}
+	s.state = stopwatchPaused
+	s.pausedAt = s.clock.Now()
+	return nil
+}
+
+// Resume 一時停止した計測を再開する
+func (s *Stopwatch) Resume() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != stopwatchPaused {
+		return ErrStopwatchNotPaused
+	}
+	s.resume(s.clock.Now())
+	s.state = stopwatchRunning
+	return nil
+}
+
+// Lap 前回のラップ(または開始)からの期間をラップとして記録して返す
+func (s *Stopwatch) Lap() (*TimeGauge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != stopwatchRunning && s.state != stopwatchPaused {
+		return nil, ErrStopwatchNotRunning
+	}
+	now := s.clock.Now()
+	if s.state == stopwatchPaused {
+		s.resume(now)
+		s.pausedAt = now
+	}
+	return s.lap(now), nil
+}
+
+// Reset 計測結果を破棄して開始前の状態に戻す
+func (s *Stopwatch) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = stopwatchIdle
+	s.begin, s.end, s.lapBegin, s.pausedAt = time.Time{}, time.Time{}, time.Time{}, time.Time{}
+	s.paused, s.lapPaused = 0, 0
+	s.laps, s.lapActive = nil, nil
+}
+
+// Gauge 計測全体の期間を返す(計測中の場合は現在時刻まで)
+func (s *Stopwatch) Gauge() *TimeGauge {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch s.state {
+	case stopwatchIdle:
+		return nil
+	case stopwatchStopped:
+		return New(s.begin, s.end)
+	default:
+		return New(s.begin, s.clock.Now())
+	}
+}
+
+// Laps 記録されたラップを返す
+func (s *Stopwatch) Laps() []*TimeGauge {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	laps := make([]*TimeGauge, len(s.laps))
+	copy(laps, s.laps)
+	return laps
+}
+
+// LapElapsed 記録されたラップ毎の一時停止を除いた計測時間を返す
+func (s *Stopwatch) LapElapsed() []time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := make([]time.Duration, len(s.lapActive))
+	copy(d, s.lapActive)
+	return d
+}
+
+// Elapsed 一時停止を除いた計測時間を返す
+func (s *Stopwatch) Elapsed() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch s.state {
+	case stopwatchIdle:
+		return 0
+	case stopwatchStopped:
+		return s.end.Sub(s.begin) - s.paused
+	case stopwatchPaused:
+		return s.pausedAt.Sub(s.begin) - s.paused
+	default:
+		return s.clock.Now().Sub(s.begin) - s.paused
+	}
+}
+
+// Paused 一時停止していた時間の合計を返す
+func (s *Stopwatch) Paused() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == stopwatchPaused {
+		return s.paused + s.clock.Now().Sub(s.pausedAt)
+	}
+	return s.paused
+}
+
+// Running 計測中(一時停止中を含む)かどうか
+func (s *Stopwatch) Running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state == stopwatchRunning || s.state == stopwatchPaused
+}
+
+// resume 一時停止時間を加算する(ロックを取得済みであること)
+func (s *Stopwatch) resume(now time.Time) {
+	if s.state != stopwatchPaused {
+		return
+	}
+	d := now.Sub(s.pausedAt)
+	s.paused += d
+	s.lapPaused += d
+}
+
+// lap ラップを記録する(ロックを取得済みであること)
+func (s *Stopwatch) lap(now time.Time) *TimeGauge {
+	tg := New(s.lapBegin, now)
+	s.laps = append(s.laps, tg)
+	s.lapActive = append(s.lapActive, tg.Duration()-s.lapPaused)
+	s.lapBegin = now
+	s.lapPaused = 0
+	return tg
+}
@@ -0,0 +1,135 @@
+package gauge
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goccha/times/pkg/clock"
+)
+
+func TestStopwatch(t *testing.T) {
+	begin, _ := time.Parse(time.RFC3339, "2020-04-01T17:00:00+09:00")
+	c := clock.NewFake(begin)
+	sw := NewStopwatch(WithClock(c))
+	if _, err := sw.Lap(); !errors.Is(err, ErrStopwatchNotRunning) {
+		t.Errorf("expected=%v, actual=%v", ErrStopwatchNotRunning, err)
+	}
+	if err := sw.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := sw.Start(); !errors.Is(err, ErrStopwatchStarted) {
+		t.Errorf("expected=%v, actual=%v", ErrStopwatchStarted, err)
+	}
+	c.Advance(10 * time.Minute)
+	lap, _ := sw.Lap()
+	if lap.Duration() != 10*time.Minute {
+		t.Errorf("expected=%v, actual=%v", 10*time.Minute, lap.Duration())
+	}
+	c.Advance(5 * time.Minute)
+	if err := sw.Pause(); err != nil {
+		t.Fatal(err)
+	}
+	c.Advance(30 * time.Minute)
+	if d := sw.Elapsed(); d != 15*time.Minute {
+		t.Errorf("expected=%v, actual=%v", 15*time.Minute, d)
+	}
+	if err := sw.Resume(); err != nil {
+		t.Fatal(err)
+	}
+	if err := sw.Resume(); !errors.Is(err, ErrStopwatchNotPaused) {
+		t.Errorf("expected=%v, actual=%v", ErrStopwatchNotPaused, err)
+	}
+	c.Advance(5 * time.Minute)
+	whole, err := sw.Stop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if whole.Duration() != 50*time.Minute {
+		t.Errorf("expected=%v, actual=%v", 50*time.Minute, whole.Duration())
+	}
+	if !whole.Begin().Equal(begin) {
+		t.Errorf("expected=%v, actual=%v", begin, whole.Begin())
+	}
+	if d := sw.Elapsed(); d != 20*time.Minute {
+		t.Errorf("expected=%v, actual=%v", 20*time.Minute, d)
+	}
+	if d := sw.Paused(); d != 30*time.Minute {
+		t.Errorf("expected=%v, actual=%v", 30*time.Minute, d)
+	}
+	laps := sw.Laps()
+	if len(laps) != 2 {
+		t.Fatalf("expected=2, actual=%d", len(laps))
+	}
+	if laps[1].Duration() != 40*time.Minute {
+		t.Errorf("expected=%v, actual=%v", 40*time.Minute, laps[1].Duration())
+	}
+	active := sw.LapElapsed()
+	if active[1] != 10*time.Minute {
+		t.Errorf("expected=%v, actual=%v", 10*time.Minute, active[1])
+	}
+	if _, err = sw.Stop(); !errors.Is(err, ErrStopwatchNotRunning) {
+		t.Errorf("expected=%v, actual=%v", ErrStopwatchNotRunning, err)
+	}
+
+	sw.Reset()
+	if sw.Running() || sw.Gauge() != nil || len(sw.Laps()) != 0 || sw.Elapsed() != 0 {
+		t.Error("expected reset stopwatch")
+	}
+	if err = sw.Start(); err != nil {
+		t.Fatal(err)
+	}
+	c.Advance(time.Minute)
+	if d := sw.Gauge().Duration(); d != time.Minute {
+		t.Errorf("expected=%v, actual=%v", time.Minute, d)
+	}
+}
+
+func TestStopwatch_LapWhilePaused(t *testing.T) {
+	begin, _ := time.Parse(time.RFC3339, "2020-04-01T17:00:00+09:00")
+	c := clock.NewFake(begin)
+	sw := StartStopwatch(WithClock(c))
+	c.Advance(time.Minute)
+	_ = sw.Pause()
+	c.Advance(time.Minute)
+	_, _ = sw.Lap()
+	c.Advance(time.Minute)
+	_ = sw.Resume()
+	c.Advance(time.Minute)
+	_, _ = sw.Stop()
+	active := sw.LapElapsed()
+	if len(active) != 2 || active[0] != time.Minute || active[1] != time.Minute {
+		t.Errorf("expected=[1m0s 1m0s], actual=%v", active)
+	}
+	if d := sw.Elapsed(); d != 2*time.Minute {
+		t.Errorf("expected=%v, actual=%v", 2*time.Minute, d)
+	}
+}
+
+func TestStopwatch_Concurrent(t *testing.T) {
+	sw := StartStopwatch()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_, _ = sw.Lap()
+				_ = sw.Elapsed()
+			}
+		}()
+	}
+	wg.Wait()
+	whole, err := sw.Stop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var total time.Duration
+	for _, lap := range sw.Laps() {
+		total += lap.Duration()
+	}
+	if total != whole.Duration() {
+		t.Errorf("expected=%v, actual=%v", whole.Duration(), total)
+	}
+}
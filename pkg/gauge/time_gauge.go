@@ -5,30 +5,36 @@ import (
 	"io"
 	"strconv"
 	"time"
+
+	"github.com/goccha/times/pkg/civil"
+	"github.com/goccha/times/pkg/zone"
 )
 
 // New 指定期間の時間計測機を生成する
 func New(begin, end time.Time) *TimeGauge {
-	tg := &TimeGauge{
-		begin: begin,
-		end:   end,
-	}
-	_ = tg.Duration()
-	return tg
+	tg := makeGauge(begin.Format("2006-01-02"), begin, end)
+	return &tg
 }
 
 // TimeGauge 時間計測機
+//
+// 日付・期間は生成時に計算し、以降は変更しないため複数の goroutine から同時に参照できる。
 type TimeGauge struct {
 	date     string
 	begin    time.Time
 	end      time.Time
-	duration *time.Duration
+	duration time.Duration
+}
+
+// makeGauge 日付を指定して時間計測機を生成する
+func makeGauge(date string, begin, end time.Time) TimeGauge {
+	return TimeGauge{date: date, begin: begin, end: end, duration: end.Sub(begin)}
 }
 
 // Date 日付を返す
 func (t *TimeGauge) Date() string {
 	if t.date == "" {
-		t.date = t.begin.Format("2006-01-02")
+		return t.begin.Format("2006-01-02")
 	}
 	return t.date
 }
@@ -45,11 +51,12 @@ func (t *TimeGauge) End() time.Time {
 
 // Duration 期間を返す
 func (t *TimeGauge) Duration() time.Duration {
-	if t.duration == nil {
-		d := t.end.Sub(t.begin)
-		t.duration = &d
-	}
-	return *t.duration
+	return t.duration
+}
+
+// WallDuration 開始日時のロケーションの壁時計での期間を返す(夏時間の切り替えを含む場合は Duration と異なる)
+func (t *TimeGauge) WallDuration() time.Duration {
+	return zone.Wall(t.end.In(t.begin.Location())).Sub(zone.Wall(t.begin))
 }
 
 // Seconds returns the duration as a floating point number of seconds.
@@ -161,96 +168,137 @@ func (t *TimeGauge) Format(s fmt.State, verb rune) {
 	}
 }
 
-// Split 期間を基準時刻で分割する
-func (t *TimeGauge) Split(hour, min, sec, ns int, loc *time.Location) []TimeGauge {
-	b := baseTime{
-		hour: hour,
-		min:  min,
-		sec:  sec,
-		ns:   ns,
-		loc:  loc,
+// SplitOption Split のオプション
+type SplitOption func(c *splitConfig)
+
+type splitConfig struct {
+	policy      zone.Policy
+	attribution Attribution
+	layout      string
+	key         func(TimeGauge) string
+}
+
+func newSplitConfig(opts []SplitOption) splitConfig {
+	c := splitConfig{policy: zone.Compatible, layout: "2006-01-02"}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// WithDSTPolicy 夏時間の切り替えで存在しない・重複する基準時刻の解釈方法を指定する(既定は zone.Compatible)
+//
+// zone.Reject を指定した場合は、存在しない・重複する基準時刻では分割しない。
+func WithDSTPolicy(policy zone.Policy) SplitOption {
+	return func(c *splitConfig) {
+		c.policy = policy
 	}
-	timeRange := t.end.Sub(t.begin)
-	if timeRange < 0 {
-		return []TimeGauge{}
+}
+
+// Split 期間を基準時刻で分割する
+//
+// 基準時刻・日付は loc の壁時計で評価し、分割した期間の日付は既定では期間を閉じる基準時刻の日付とする
+// (WithAttribution, WithKeyLayout, WithKeyFunc で変更できる)。
+// 分割した期間は開始日時の順に返す。終了日時が開始日時以前の場合は空のスライスを返す(NewChecked, Validate で事前に検証できる)。
+func (t *TimeGauge) Split(hour, min, sec, ns int, loc *time.Location, opts ...SplitOption) []TimeGauge {
+	times := make([]TimeGauge, 0)
+	if !t.end.After(t.begin) {
+		return times
 	}
-	m := make(map[string]*TimeGauge)
-	split(b, t.begin, timeRange, m)
-	times := make([]TimeGauge, 0, len(m))
-	for _, v := range m {
-		times = append(times, *v)
+	c := newSplitConfig(opts)
+	b := newBaseTime(hour, min, sec, ns, loc, c.policy)
+	days := make([]time.Time, 0)
+	begin := t.begin
+	for day, base := b.first(t.begin); ; day, base = b.next(day) {
+		days = append(days, day)
+		if !t.end.After(base) {
+			times = append(times, makeGauge("", begin, t.end))
+			break
+		}
+		times = append(times, makeGauge("", begin, base))
+		begin = base
 	}
+	c.attribute(t, times, days, b)
 	return times
 }
 
+// SplitAt 期間を基準時刻で分割する(基準時刻が範囲外の場合はエラー)
+func (t *TimeGauge) SplitAt(tod civil.TimeOfDay, loc *time.Location, opts ...SplitOption) ([]TimeGauge, error) {
+	if !tod.IsValid() {
+		return nil, fmt.Errorf("gauge: invalid time of day %s", tod)
+	}
+	return t.Split(tod.Hour, tod.Minute, tod.Second, tod.Nanosecond, loc, opts...), nil
+}
+
+// Boundaries 期間内(開始日時・終了日時を含まない)の基準時刻を返す(基準時刻が範囲外の場合はエラー)
+func (t *TimeGauge) Boundaries(tod civil.TimeOfDay, loc *time.Location, opts ...SplitOption) ([]time.Time, error) {
+	if !tod.IsValid() {
+		return nil, fmt.Errorf("gauge: invalid time of day %s", tod)
+	}
+	times := make([]time.Time, 0)
+	if !t.end.After(t.begin) {
+		return times, nil
+	}
+	b := newBaseTime(tod.Hour, tod.Minute, tod.Second, tod.Nanosecond, loc, newSplitConfig(opts).policy)
+	for day, base := b.first(t.begin); base.Before(t.end); day, base = b.next(day) {
+		times = append(times, base)
+	}
+	return times, nil
+}
+
 // baseTime 基準時刻
 type baseTime struct {
-	hour int
-	min  int
-	sec  int
-	ns   int
-	loc  *time.Location
+	hour   int
+	min    int
+	sec    int
+	ns     int
+	loc    *time.Location
+	policy zone.Policy
 }
 
-// Time 指定日の基準時刻(time.Time)を返す
-func (t baseTime) Time(tm time.Time) time.Time {
-	return time.Date(tm.Year(), tm.Month(), tm.Day(), t.hour, t.min, t.sec, t.ns, t.loc)
+func newBaseTime(hour, min, sec, ns int, loc *time.Location, policy zone.Policy) baseTime {
+	return baseTime{
+		hour:   hour,
+		min:    min,
+		sec:    sec,
+		ns:     ns,
+		loc:    loc,
+		policy: policy,
+	}
+}
+
+// Time 指定日(UTC で表現した日付)の基準時刻(time.Time)を返す
+func (t baseTime) Time(day time.Time) (time.Time, bool) {
+	tm, err := zone.Date(day.Year(), day.Month(), day.Day(), t.hour, t.min, t.sec, t.ns, t.loc, t.policy)
+	return tm, err == nil
 }
 
-// split 開始日時からの経過時間を基準時刻で日付毎に分割する
-func split(b baseTime, tm time.Time, timeRange time.Duration, times map[string]*TimeGauge) {
-	if timeRange <= 0 {
-		return
+// first tm より後の最初の基準時刻とその日付を返す
+func (t baseTime) first(tm time.Time) (time.Time, time.Time) {
+	y, m, d := tm.In(t.loc).Date()
+	day, base := t.next(time.Date(y, m, d-1, 0, 0, 0, 0, time.UTC))
+	for !base.After(tm) {
+		day, base = t.next(day)
 	}
-	base := b.Time(tm)
-	diff := base.Sub(tm)
-	if diff > 0 { // 開始が基準時刻よりも前
-		key := tm.Format("2006-01-02")
-		if timeRange < diff { // 基準時刻よりも前に終了している場合
-			times[key] = &TimeGauge{
-				date:  key,
-				begin: tm,
-				end:   tm.Add(timeRange),
-			}
-			return
-		} else {
-			times[key] = &TimeGauge{
-				date:  key,
-				begin: tm,
-				end:   base,
-			}
-			timeRange -= diff
-			split(b, base, timeRange, times)
-			return
+	return day, base
+}
+
+// next 翌日以降の最初の基準時刻とその日付を返す(基準時刻が存在しない日は飛ばす)
+func (t baseTime) next(day time.Time) (time.Time, time.Time) {
+	for {
+		day = day.AddDate(0, 0, 1)
+		if base, ok := t.Time(day); ok {
+			return day, base
 		}
-	} else {
-		base = base.AddDate(0, 0, 1) // 基準日時を翌日にする
-		key := base.Format("2006-01-02")
-		diff = base.Sub(tm)   // 基準日時までの時間を算出
-		if timeRange < diff { // 基準日時以前に開始している場合
-			if v, ok := times[key]; ok {
-				v.end = tm.Add(timeRange)
-			} else {
-				times[key] = &TimeGauge{
-					date:  key,
-					begin: tm,
-					end:   tm.Add(timeRange),
-				}
-			}
-			return
-		} else { // 基準日時以降に開始している場合
-			if v, ok := times[key]; ok {
-				v.end = base
-			} else {
-				times[key] = &TimeGauge{
-					date:  key,
-					begin: tm,
-					end:   base,
-				}
-			}
-			timeRange -= diff
-			split(b, base, timeRange, times)
-			return
+	}
+}
+
+// prev 前日以前の最初の基準時刻の日付を返す(基準時刻が存在しない日は飛ばす)
+func (t baseTime) prev(day time.Time) time.Time {
+	for {
+		day = day.AddDate(0, 0, -1)
+		if _, ok := t.Time(day); ok {
+			return day
 		}
 	}
 }
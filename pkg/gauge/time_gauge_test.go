@@ -2,8 +2,12 @@ package gauge
 
 import (
 	"fmt"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/goccha/times/pkg/civil"
+	"github.com/goccha/times/pkg/zone"
 )
 
 func TestCalc(t *testing.T) {
@@ -154,3 +158,210 @@ func TestTimeGauge_Overlap(t *testing.T) {
 		t.Error("expected=false, actual=true")
 	}
 }
+
+func splitString(times []TimeGauge) string {
+	s := ""
+	for _, v := range times {
+		s += fmt.Sprintf("%s[%s,%s)", v.Date(), v.Begin().Format(time.RFC3339), v.End().Format(time.RFC3339))
+	}
+	return s
+}
+
+func TestTimeGauge_Split_Order(t *testing.T) {
+	begin, _ := time.Parse(time.RFC3339, "2020-04-01T23:00:00+09:00")
+	end, _ := time.Parse(time.RFC3339, "2020-04-05T07:00:00+09:00")
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	times := New(begin, end).Split(18, 0, 0, 0, tokyo)
+	expected := []string{"2020-04-02", "2020-04-03", "2020-04-04", "2020-04-05"}
+	if len(times) != len(expected) {
+		t.Fatalf("expected=%d, actual=%d", len(expected), len(times))
+	}
+	for i, v := range times {
+		if v.Date() != expected[i] {
+			t.Errorf("[%d] expected=%s, actual=%s", i, expected[i], v.Date())
+		}
+		if i > 0 && !v.Begin().Equal(times[i-1].End()) {
+			t.Errorf("[%d] expected=%v, actual=%v", i, times[i-1].End(), v.Begin())
+		}
+	}
+}
+
+func TestTimeGauge_Split_DST(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	london, _ := time.LoadLocation("Europe/London")
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	tests := []struct {
+		name     string
+		begin    time.Time
+		end      time.Time
+		hour     int
+		min      int
+		loc      *time.Location
+		opts     []SplitOption
+		expected string
+	}{
+		{
+			name:  "23 hours day",
+			begin: time.Date(2020, 3, 7, 12, 0, 0, 0, ny), end: time.Date(2020, 3, 9, 12, 0, 0, 0, ny), loc: ny,
+			expected: "2020-03-08[2020-03-07T12:00:00-05:00,2020-03-08T00:00:00-05:00)" +
+				"2020-03-09[2020-03-08T00:00:00-05:00,2020-03-09T00:00:00-04:00)" +
+				"2020-03-10[2020-03-09T00:00:00-04:00,2020-03-09T12:00:00-04:00)",
+		},
+		{
+			name:  "25 hours day",
+			begin: time.Date(2020, 10, 24, 12, 0, 0, 0, london), end: time.Date(2020, 10, 26, 12, 0, 0, 0, london), loc: london,
+			expected: "2020-10-25[2020-10-24T12:00:00+01:00,2020-10-25T00:00:00+01:00)" +
+				"2020-10-26[2020-10-25T00:00:00+01:00,2020-10-26T00:00:00Z)" +
+				"2020-10-27[2020-10-26T00:00:00Z,2020-10-26T12:00:00Z)",
+		},
+		{
+			name:  "nonexistent boundary",
+			begin: time.Date(2020, 3, 7, 20, 0, 0, 0, ny), end: time.Date(2020, 3, 8, 12, 0, 0, 0, ny), hour: 2, min: 30, loc: ny,
+			expected: "2020-03-08[2020-03-07T20:00:00-05:00,2020-03-08T03:30:00-04:00)" +
+				"2020-03-09[2020-03-08T03:30:00-04:00,2020-03-08T12:00:00-04:00)",
+		},
+		{
+			name:  "nonexistent boundary earlier",
+			begin: time.Date(2020, 3, 7, 20, 0, 0, 0, ny), end: time.Date(2020, 3, 8, 12, 0, 0, 0, ny), hour: 2, min: 30, loc: ny,
+			opts: []SplitOption{WithDSTPolicy(zone.Earlier)},
+			expected: "2020-03-08[2020-03-07T20:00:00-05:00,2020-03-08T01:30:00-05:00)" +
+				"2020-03-09[2020-03-08T01:30:00-05:00,2020-03-08T12:00:00-04:00)",
+		},
+		{
+			name:  "nonexistent boundary reject",
+			begin: time.Date(2020, 3, 7, 20, 0, 0, 0, ny), end: time.Date(2020, 3, 8, 12, 0, 0, 0, ny), hour: 2, min: 30, loc: ny,
+			opts:     []SplitOption{WithDSTPolicy(zone.Reject)},
+			expected: "2020-03-09[2020-03-07T20:00:00-05:00,2020-03-08T12:00:00-04:00)",
+		},
+		{
+			name:  "ambiguous boundary",
+			begin: time.Date(2020, 10, 24, 20, 0, 0, 0, london), end: time.Date(2020, 10, 25, 12, 0, 0, 0, london), hour: 1, min: 30, loc: london,
+			expected: "2020-10-25[2020-10-24T20:00:00+01:00,2020-10-25T01:30:00+01:00)" +
+				"2020-10-26[2020-10-25T01:30:00+01:00,2020-10-25T12:00:00Z)",
+		},
+		{
+			name:  "ambiguous boundary later",
+			begin: time.Date(2020, 10, 24, 20, 0, 0, 0, london), end: time.Date(2020, 10, 25, 12, 0, 0, 0, london), hour: 1, min: 30, loc: london,
+			opts: []SplitOption{WithDSTPolicy(zone.Later)},
+			expected: "2020-10-25[2020-10-24T20:00:00+01:00,2020-10-25T01:30:00Z)" +
+				"2020-10-26[2020-10-25T01:30:00Z,2020-10-25T12:00:00Z)",
+		},
+		{
+			name:  "different location",
+			begin: time.Date(2020, 4, 1, 9, 0, 0, 0, tokyo), end: time.Date(2020, 4, 1, 15, 0, 0, 0, tokyo), loc: ny,
+			expected: "2020-04-01[2020-04-01T09:00:00+09:00,2020-04-01T00:00:00-04:00)" +
+				"2020-04-02[2020-04-01T00:00:00-04:00,2020-04-01T15:00:00+09:00)",
+		},
+	}
+	for _, tt := range tests {
+		times := New(tt.begin, tt.end).Split(tt.hour, tt.min, 0, 0, tt.loc, tt.opts...)
+		if actual := splitString(times); actual != tt.expected {
+			t.Errorf("%s: expected=%s, actual=%s", tt.name, tt.expected, actual)
+		}
+	}
+}
+
+func TestTimeGauge_WallDuration(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	london, _ := time.LoadLocation("Europe/London")
+	tests := []struct {
+		tg       *TimeGauge
+		duration time.Duration
+		wall     time.Duration
+	}{
+		{New(time.Date(2020, 3, 8, 1, 0, 0, 0, ny), time.Date(2020, 3, 8, 3, 0, 0, 0, ny)), time.Hour, 2 * time.Hour},
+		{New(time.Date(2020, 10, 25, 0, 30, 0, 0, london), time.Date(2020, 10, 25, 1, 30, 0, 0, time.UTC).In(london)), 2 * time.Hour, time.Hour},
+		{New(time.Date(2020, 3, 8, 0, 0, 0, 0, ny), time.Date(2020, 3, 9, 0, 0, 0, 0, ny)), 23 * time.Hour, 24 * time.Hour},
+		{New(time.Date(2020, 4, 1, 9, 0, 0, 0, ny), time.Date(2020, 4, 1, 9, 0, 0, 0, london)), -5 * time.Hour, -5 * time.Hour},
+	}
+	for i, tt := range tests {
+		if actual := tt.tg.Duration(); actual != tt.duration {
+			t.Errorf("[%d] expected=%v, actual=%v", i, tt.duration, actual)
+		}
+		if actual := tt.tg.WallDuration(); actual != tt.wall {
+			t.Errorf("[%d] expected=%v, actual=%v", i, tt.wall, actual)
+		}
+	}
+}
+
+func TestTimeGauge_SplitAt(t *testing.T) {
+	begin, _ := time.Parse(time.RFC3339, "2020-04-01T17:00:00+09:00")
+	end, _ := time.Parse(time.RFC3339, "2020-04-01T19:00:00+09:00")
+	rec := New(begin, end)
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	tod, _ := civil.ParseTimeOfDay("18:00")
+	times, err := rec.SplitAt(tod, tokyo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := rec.Split(18, 0, 0, 0, tokyo)
+	if len(times) != 2 || len(times) != len(expected) {
+		t.Fatalf("expected=2, actual=%d", len(times))
+	}
+	if actual, expected := splitString(times), splitString(expected); actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+	if _, err = rec.SplitAt(civil.TimeOfDay{Hour: 25, Minute: 61}, tokyo); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestTimeGauge_Boundaries(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	rec := New(time.Date(2020, 3, 7, 0, 0, 0, 0, ny), time.Date(2020, 3, 9, 2, 30, 0, 0, ny))
+	times, err := rec.Boundaries(civil.TimeOfDay{Hour: 2, Minute: 30}, ny)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"2020-03-07T02:30:00-05:00", "2020-03-08T03:30:00-04:00"}
+	if len(times) != len(expected) {
+		t.Fatalf("expected=%v, actual=%v", expected, times)
+	}
+	for i, v := range times {
+		if v.Format(time.RFC3339) != expected[i] {
+			t.Errorf("[%d] expected=%s, actual=%s", i, expected[i], v.Format(time.RFC3339))
+		}
+	}
+	if times, _ = rec.Boundaries(civil.TimeOfDay{Hour: 2, Minute: 30}, ny, WithDSTPolicy(zone.Reject)); len(times) != 1 {
+		t.Errorf("expected=1, actual=%d", len(times))
+	}
+	if _, err = rec.Boundaries(civil.TimeOfDay{Minute: -1}, ny); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestTimeGauge_Concurrent(t *testing.T) {
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	rec := New(time.Date(2020, 4, 1, 22, 0, 0, 0, tokyo), time.Date(2020, 4, 4, 6, 0, 0, 0, tokyo))
+	segments := rec.Split(5, 0, 0, 0, tokyo)
+	read := func(tg *TimeGauge) string {
+		return fmt.Sprintf("%s %v %h:%m %s %v", tg.Date(), tg.Duration(), tg, tg, tg.Interval(), tg.Contains(tg.Begin()))
+	}
+	expected := make([]string, len(segments)+1)
+	expected[0] = read(rec)
+	for i := range segments {
+		expected[i+1] = read(&segments[i])
+	}
+	var zero TimeGauge
+	var wg sync.WaitGroup
+	for n := 0; n < 8; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				if actual := read(rec); actual != expected[0] {
+					t.Errorf("expected=%s, actual=%s", expected[0], actual)
+				}
+				for j := range segments {
+					if actual := read(&segments[j]); actual != expected[j+1] {
+						t.Errorf("[%d] expected=%s, actual=%s", j, expected[j+1], actual)
+					}
+				}
+				_ = zero.Date()
+				_ = zero.Duration()
+				_ = rec.Split(5, 0, 0, 0, tokyo)
+			}
+		}()
+	}
+	wg.Wait()
+}
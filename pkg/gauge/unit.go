@@ -0,0 +1,147 @@
+package gauge
+
+import (
+	"time"
+
+	"github.com/goccha/times/pkg/civil"
+	"github.com/goccha/times/pkg/weeks"
+)
+
+// Unit 暦の単位
+type Unit int
+
+const (
+	// Day 日
+	Day Unit = iota
+	// Week 週
+	Week
+	// Month 月
+	Month
+	// Quarter 四半期(既定は1月・4月・7月・10月始まり)
+	Quarter
+	// Year 年(既定は1月始まり)
+	Year
+)
+
+// String 単位の名前を返す
+func (u Unit) String() string {
+	switch u {
+	case Day:
+		return "day"
+	case Week:
+		return "week"
+	case Month:
+		return "month"
+	case Quarter:
+		return "quarter"
+	case Year:
+		return "year"
+	}
+	return "unknown"
+}
+
+// UnitOption StartOf, EndOf, GaugeOf のオプション
+type UnitOption func(c *unitConfig)
+
+type unitConfig struct {
+	rule      weeks.Rule
+	boundary  civil.TimeOfDay
+	yearStart time.Month
+}
+
+// WithWeekRule 週の開始曜日を指定する(既定は weeks.Sunday)
+func WithWeekRule(rule weeks.Rule) UnitOption {
+	return func(c *unitConfig) {
+		c.rule = rule
+	}
+}
+
+// WithDayBoundary 日の区切り時刻を指定する(既定は0時、05:00 を指定した場合は 05:00 から翌日の 05:00 までを1日とする)
+func WithDayBoundary(boundary civil.TimeOfDay) UnitOption {
+	return func(c *unitConfig) {
+		c.boundary = boundary
+	}
+}
+
+// WithYearStart 年の開始月を指定する(既定は1月、4月始まりの年度は time.April、Quarter と Year に適用する)
+func WithYearStart(m time.Month) UnitOption {
+	return func(c *unitConfig) {
+		c.yearStart = m
+	}
+}
+
+// StartOf 日時を含む単位の開始日時を返す(日時のロケーションの壁時計で評価する)
+func StartOf(t time.Time, u Unit, opts ...UnitOption) time.Time {
+	c := newUnitConfig(opts)
+	return c.boundary.On(c.start(c.dateOf(t), u), t.Location())
+}
+
+// EndOf 日時を含む単位の終了日時(次の単位の開始日時、期間に含まない)を返す
+func EndOf(t time.Time, u Unit, opts ...UnitOption) time.Time {
+	c := newUnitConfig(opts)
+	return c.boundary.On(nextUnit(c.start(c.dateOf(t), u), u), t.Location())
+}
+
+// GaugeOf 日時を含む単位の期間を返す
+func GaugeOf(t time.Time, u Unit, opts ...UnitOption) *TimeGauge {
+	c := newUnitConfig(opts)
+	start := c.start(c.dateOf(t), u)
+	return New(c.boundary.On(start, t.Location()), c.boundary.On(nextUnit(start, u), t.Location()))
+}
+
+func newUnitConfig(opts []UnitOption) unitConfig {
+	c := unitConfig{rule: weeks.Sunday, yearStart: time.January}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.yearStart < time.January || c.yearStart > time.December {
+		c.yearStart = time.January
+	}
+	return c
+}
+
+// dateOf 日時が属する日(日の区切り時刻より前の場合は前日)を返す
+func (c unitConfig) dateOf(t time.Time) civil.Date {
+	d := civil.DateOf(t)
+	if t.Before(c.boundary.On(d, t.Location())) {
+		return d.AddDays(-1)
+	}
+	return d
+}
+
+// start 日付を含む単位の開始日を返す
+func (c unitConfig) start(d civil.Date, u Unit) civil.Date {
+	switch u {
+	case Week:
+		return c.rule.StartDate(d)
+	case Month:
+		return civil.Date{Year: d.Year, Month: d.Month, Day: 1}
+	case Quarter:
+		return c.monthStart(d, 3)
+	case Year:
+		return c.monthStart(d, 12)
+	}
+	return d
+}
+
+// monthStart 年の開始月から months ヶ月毎に区切った期間のうち日付を含む期間の開始日を返す
+func (c unitConfig) monthStart(d civil.Date, months int) civil.Date {
+	n := int(d.Month) - int(c.yearStart)
+	n -= (n%months + months) % months
+	return civil.Date{Year: d.Year, Month: c.yearStart, Day: 1}.AddDate(0, n, 0)
+}
+
+// nextUnit 単位の開始日の次の単位の開始日を返す
+func nextUnit(d civil.Date, u Unit) civil.Date {
+	switch u {
+	case Week:
+		return d.AddDays(7)
+	case Month:
+		return d.AddDate(0, 1, 0)
+	case Quarter:
+		return d.AddDate(0, 3, 0)
+	case Year:
+		return d.AddDate(1, 0, 0)
+	}
+	return d.AddDays(1)
+}
@@ -0,0 +1,61 @@
+package gauge
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goccha/times/pkg/civil"
+	"github.com/goccha/times/pkg/weeks"
+)
+
+func TestStartOf(t *testing.T) {
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	tm := time.Date(2020, 5, 13, 3, 30, 0, 0, tokyo) // 水曜日
+	boundary := WithDayBoundary(civil.TimeOfDay{Hour: 5})
+	tests := []struct {
+		unit  Unit
+		opts  []UnitOption
+		start string
+		end   string
+	}{
+		{Day, nil, "2020-05-13T00:00:00+09:00", "2020-05-14T00:00:00+09:00"},
+		{Day, []UnitOption{boundary}, "2020-05-12T05:00:00+09:00", "2020-05-13T05:00:00+09:00"},
+		{Week, nil, "2020-05-10T00:00:00+09:00", "2020-05-17T00:00:00+09:00"},
+		{Week, []UnitOption{WithWeekRule(weeks.ISO)}, "2020-05-11T00:00:00+09:00", "2020-05-18T00:00:00+09:00"},
+		{Month, nil, "2020-05-01T00:00:00+09:00", "2020-06-01T00:00:00+09:00"},
+		{Quarter, nil, "2020-04-01T00:00:00+09:00", "2020-07-01T00:00:00+09:00"},
+		{Year, []UnitOption{boundary}, "2020-01-01T05:00:00+09:00", "2021-01-01T05:00:00+09:00"},
+		{Quarter, []UnitOption{WithYearStart(time.February)}, "2020-05-01T00:00:00+09:00", "2020-08-01T00:00:00+09:00"},
+		{Year, []UnitOption{WithYearStart(time.April)}, "2020-04-01T00:00:00+09:00", "2021-04-01T00:00:00+09:00"},
+		{Year, []UnitOption{WithYearStart(time.June)}, "2019-06-01T00:00:00+09:00", "2020-06-01T00:00:00+09:00"},
+	}
+	for _, tt := range tests {
+		if actual := StartOf(tm, tt.unit, tt.opts...).Format(time.RFC3339); actual != tt.start {
+			t.Errorf("%s: expected=%s, actual=%s", tt.unit, tt.start, actual)
+		}
+		if actual := EndOf(tm, tt.unit, tt.opts...).Format(time.RFC3339); actual != tt.end {
+			t.Errorf("%s: expected=%s, actual=%s", tt.unit, tt.end, actual)
+		}
+		tg := GaugeOf(tm, tt.unit, tt.opts...)
+		if actual := tg.Begin().Format(time.RFC3339) + "/" + tg.End().Format(time.RFC3339); actual != tt.start+"/"+tt.end {
+			t.Errorf("%s: expected=%s/%s, actual=%s", tt.unit, tt.start, tt.end, actual)
+		}
+	}
+
+	// 単位の区切りを跨ぐ日の区切り時刻: 2021-01-01 04:00 は 2020 年の最終日に属する
+	tm = time.Date(2021, 1, 1, 4, 0, 0, 0, tokyo)
+	if actual := StartOf(tm, Quarter, boundary).Format(time.RFC3339); actual != "2020-10-01T05:00:00+09:00" {
+		t.Errorf("expected=2020-10-01T05:00:00+09:00, actual=%s", actual)
+	}
+}
+
+func TestStartOf_DST(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	tg := GaugeOf(time.Date(2020, 3, 8, 12, 0, 0, 0, ny), Day)
+	if tg.Duration() != 23*time.Hour {
+		t.Errorf("expected=%v, actual=%v", 23*time.Hour, tg.Duration())
+	}
+	if tg.Date() != "2020-03-08" {
+		t.Errorf("expected=2020-03-08, actual=%s", tg.Date())
+	}
+}
@@ -0,0 +1,139 @@
+package gauge
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrInverted 終了日時が開始日時より前
+	ErrInverted = errors.New("gauge: end is before begin")
+	// ErrZeroLength 期間の長さが0
+	ErrZeroLength = errors.New("gauge: zero-length range")
+	// ErrLocationMismatch 開始日時と終了日時のロケーションが異なる
+	ErrLocationMismatch = errors.New("gauge: begin and end have different locations")
+	// ErrTooLong 期間が上限を超えている
+	ErrTooLong = errors.New("gauge: range exceeds maximum duration")
+)
+
+// RangeError 期間の検証エラー(Err は ErrInverted などのセンチネルエラー)
+type RangeError struct {
+	Begin time.Time
+	End   time.Time
+	Err   error
+}
+
+// Error implements the error interface.
+func (e *RangeError) Error() string {
+	return fmt.Sprintf("%v: %s/%s", e.Err, formatISOTime(e.Begin), formatISOTime(e.End))
+}
+
+// Unwrap 元のエラーを返す
+func (e *RangeError) Unwrap() error {
+	return e.Err
+}
+
+// CheckOption 期間の検証のオプション
+type CheckOption func(c *checkConfig)
+
+type checkConfig struct {
+	normalize     bool
+	allowZero     bool
+	allowMixedLoc bool
+	max           time.Duration
+}
+
+// Normalize 終了日時が開始日時より前の場合はエラーとせず入れ替える
+func Normalize() CheckOption {
+	return func(c *checkConfig) {
+		c.normalize = true
+	}
+}
+
+// AllowZero 長さ0の期間を許可する
+func AllowZero() CheckOption {
+	return func(c *checkConfig) {
+		c.allowZero = true
+	}
+}
+
+// AllowMixedLocations 開始日時と終了日時のロケーションが異なることを許可する
+func AllowMixedLocations() CheckOption {
+	return func(c *checkConfig) {
+		c.allowMixedLoc = true
+	}
+}
+
+// MaxDuration 期間の上限を指定する(0以下は上限なし)
+func MaxDuration(d time.Duration) CheckOption {
+	return func(c *checkConfig) {
+		c.max = d
+	}
+}
+
+// NewChecked 期間を検証して時間計測機を生成する
+//
+// 検証に失敗した場合は *RangeError を返し、errors.Is で ErrInverted, ErrZeroLength,
+// ErrLocationMismatch, ErrTooLong を判定できる。
+func NewChecked(begin, end time.Time, opts ...CheckOption) (*TimeGauge, error) {
+	c := &checkConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.normalize && end.Before(begin) {
+		begin, end = end, begin
+	}
+	if err := c.check(begin, end); err != nil {
+		return nil, err
+	}
+	return New(begin, end), nil
+}
+
+// Validate 期間を検証する(検証内容は NewChecked と同じで、Normalize は無視する)
+func (t *TimeGauge) Validate(opts ...CheckOption) error {
+	c := &checkConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c.check(t.begin, t.end)
+}
+
+func (c *checkConfig) check(begin, end time.Time) error {
+	if err := checkOrder(begin, end); err != nil {
+		return err
+	}
+	if !c.allowZero && end.Equal(begin) {
+		return &RangeError{Begin: begin, End: end, Err: ErrZeroLength}
+	}
+	if !c.allowMixedLoc && !sameLocation(begin, end) {
+		return &RangeError{Begin: begin, End: end, Err: ErrLocationMismatch}
+	}
+	if c.max > 0 && end.Sub(begin) > c.max {
+		return &RangeError{Begin: begin, End: end, Err: ErrTooLong}
+	}
+	return nil
+}
+
+// sameLocation 開始日時と終了日時のロケーションが同じかを返す(名前の無い固定オフセットはオフセットで比較する)
+func sameLocation(begin, end time.Time) bool {
+	if begin.Location() == end.Location() {
+		return true
+	}
+	if name := begin.Location().String(); name != end.Location().String() {
+		return false
+	} else if name != "" {
+		return true
+	}
+	_, bo := begin.Zone()
+	_, eo := end.Zone()
+	return bo == eo
+}
+
+// checkOrder 終了日時が開始日時より前の場合は ErrInverted の *RangeError を返す
+func checkOrder(begin, end time.Time) error {
+	if end.Before(begin) {
+		return &RangeError{Begin: begin, End: end, Err: ErrInverted}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package gauge
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewChecked(t *testing.T) {
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	tokyo2, _ := time.LoadLocation("Asia/Tokyo")
+	begin := time.Date(2020, 4, 1, 9, 0, 0, 0, tokyo)
+	tests := []struct {
+		name     string
+		begin    time.Time
+		end      time.Time
+		opts     []CheckOption
+		expected error
+	}{
+		{"valid", begin, begin.Add(time.Hour), nil, nil},
+		{"inverted", begin, begin.Add(-time.Hour), nil, ErrInverted},
+		{"zero", begin, begin, nil, ErrZeroLength},
+		{"allow zero", begin, begin, []CheckOption{AllowZero()}, nil},
+		{"mismatch", begin, begin.Add(time.Hour).UTC(), nil, ErrLocationMismatch},
+		{"allow mismatch", begin, begin.Add(time.Hour).UTC(), []CheckOption{AllowMixedLocations()}, nil},
+		{"same name", begin, time.Date(2020, 4, 1, 10, 0, 0, 0, tokyo2), nil, nil},
+		{"same offset", begin.In(time.FixedZone("", 9*60*60)), begin.Add(time.Hour).In(time.FixedZone("", 9*60*60)), nil, nil},
+		{"too long", begin, begin.Add(25 * time.Hour), []CheckOption{MaxDuration(24 * time.Hour)}, ErrTooLong},
+		{"max", begin, begin.Add(24 * time.Hour), []CheckOption{MaxDuration(24 * time.Hour)}, nil},
+	}
+	for _, tt := range tests {
+		tg, err := NewChecked(tt.begin, tt.end, tt.opts...)
+		if tt.expected == nil {
+			if err != nil || tg == nil {
+				t.Errorf("%s: unexpected error %v", tt.name, err)
+			}
+			continue
+		}
+		if !errors.Is(err, tt.expected) || tg != nil {
+			t.Errorf("%s: expected=%v, actual=%v", tt.name, tt.expected, err)
+		}
+		var re *RangeError
+		if !errors.As(err, &re) || !re.Begin.Equal(tt.begin) || !re.End.Equal(tt.end) {
+			t.Errorf("%s: expected *RangeError, actual=%#v", tt.name, err)
+		}
+	}
+}
+
+func TestNewChecked_Normalize(t *testing.T) {
+	begin := time.Date(2020, 4, 1, 9, 0, 0, 0, time.UTC)
+	end := begin.Add(-time.Hour)
+	tg, err := NewChecked(begin, end, Normalize())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tg.Begin().Equal(end) || !tg.End().Equal(begin) || tg.Duration() != time.Hour {
+		t.Errorf("expected=%s/%s, actual=%s", end, begin, tg.Interval())
+	}
+	if err := New(begin, end).Validate(Normalize()); !errors.Is(err, ErrInverted) {
+		t.Errorf("expected=%v, actual=%v", ErrInverted, err)
+	}
+}
+
+func TestErrInverted_Codecs(t *testing.T) {
+	var tg TimeGauge
+	if err := json.Unmarshal([]byte(`{"begin":"2020-04-01T10:00:00Z","end":"2020-04-01T09:00:00Z"}`), &tg); !errors.Is(err, ErrInverted) {
+		t.Errorf("expected=%v, actual=%v", ErrInverted, err)
+	}
+	if _, err := ParseInterval("2020-04-01T10:00:00Z/2020-04-01T09:00:00Z"); !errors.Is(err, ErrInverted) {
+		t.Errorf("expected=%v, actual=%v", ErrInverted, err)
+	}
+	if err := tg.Scan(`["2020-04-01 10:00:00+00","2020-04-01 09:00:00+00")`); !errors.Is(err, ErrInverted) {
+		t.Errorf("expected=%v, actual=%v", ErrInverted, err)
+	}
+	begin := time.Date(2020, 4, 1, 10, 0, 0, 0, time.UTC)
+	if _, err := New(begin, begin.Add(-time.Hour)).Value(); !errors.Is(err, ErrInverted) {
+		t.Errorf("expected=%v, actual=%v", ErrInverted, err)
+	}
+}
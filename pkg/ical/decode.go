@@ -0,0 +1,382 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/goccha/times/pkg/gauge"
+	"github.com/goccha/times/pkg/recurrence"
+)
+
+// property iCalendar のプロパティ
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// component iCalendar のコンポーネント
+type component struct {
+	name       string
+	properties []*property
+	children   []*component
+}
+
+func (c *component) get(name string) *property {
+	for _, p := range c.properties {
+		if p.name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+func (c *component) all(name string) []*property {
+	var props []*property
+	for _, p := range c.properties {
+		if p.name == name {
+			props = append(props, p)
+		}
+	}
+	return props
+}
+
+// Decoder iCalendar 形式を読み込む
+type Decoder struct {
+	r   io.Reader
+	loc *time.Location
+}
+
+// NewDecoder iCalendar 形式を読み込む Decoder を生成する
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: r, loc: time.Local}
+}
+
+// SetLocation 終日イベント・タイムゾーンの指定が無い日時を解釈するロケーションを指定する(既定は time.Local)
+func (d *Decoder) SetLocation(loc *time.Location) {
+	if loc != nil {
+		d.loc = loc
+	}
+}
+
+// Decode iCalendar 形式のカレンダーを読み込む
+//
+// TZID は IANA のタイムゾーン名として解釈し、解釈できない場合は VTIMEZONE の標準時のオフセットを使用する。
+func (d *Decoder) Decode() (*Calendar, error) {
+	lines, err := unfold(d.r)
+	if err != nil {
+		return nil, err
+	}
+	var stack []*component
+	var root *component
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		p, err := parseProperty(line)
+		if err != nil {
+			return nil, fmt.Errorf("ical: line %d: %w", i+1, err)
+		}
+		switch p.name {
+		case "BEGIN":
+			c := &component{name: strings.ToUpper(p.value)}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, c)
+			} else if root == nil {
+				root = c
+			}
+			stack = append(stack, c)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].name != strings.ToUpper(p.value) {
+				return nil, fmt.Errorf("ical: line %d: unexpected END:%s", i+1, p.value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("ical: line %d: property outside of component", i+1)
+			}
+			c := stack[len(stack)-1]
+			c.properties = append(c.properties, p)
+		}
+	}
+	if root == nil || root.name != "VCALENDAR" {
+		return nil, fmt.Errorf("ical: VCALENDAR not found")
+	}
+	if len(stack) != 0 {
+		return nil, fmt.Errorf("ical: unterminated component %s", stack[len(stack)-1].name)
+	}
+	cal := &Calendar{}
+	if p := root.get("PRODID"); p != nil {
+		cal.ProdID = unescapeText(p.value)
+	}
+	if p := root.get("X-WR-CALNAME"); p != nil {
+		cal.Name = unescapeText(p.value)
+	}
+	zones := make(map[string]*time.Location)
+	for _, c := range root.children {
+		if c.name == "VTIMEZONE" {
+			if err := addZone(zones, c); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, c := range root.children {
+		if c.name != "VEVENT" {
+			continue
+		}
+		ev, err := d.event(c, zones)
+		if err != nil {
+			return nil, err
+		}
+		cal.Events = append(cal.Events, ev)
+	}
+	return cal, nil
+}
+
+// Decode iCalendar 形式の文字列を読み込む
+func Decode(s string) (*Calendar, error) {
+	return NewDecoder(strings.NewReader(s)).Decode()
+}
+
+func (d *Decoder) event(c *component, zones map[string]*time.Location) (*Event, error) {
+	ev := &Event{}
+	if p := c.get("UID"); p != nil {
+		ev.UID = unescapeText(p.value)
+	}
+	if p := c.get("SUMMARY"); p != nil {
+		ev.Summary = unescapeText(p.value)
+	}
+	if p := c.get("DESCRIPTION"); p != nil {
+		ev.Description = unescapeText(p.value)
+	}
+	if p := c.get("DTSTAMP"); p != nil {
+		ev.Stamp, _ = d.dateTime(p, zones)
+	}
+	start := c.get("DTSTART")
+	if start == nil {
+		return nil, fmt.Errorf("ical: VEVENT %q: DTSTART is required", ev.UID)
+	}
+	begin, err := d.dateTime(start, zones)
+	if err != nil {
+		return nil, fmt.Errorf("ical: VEVENT %q: %w", ev.UID, err)
+	}
+	ev.AllDay = isDate(start)
+	end := begin
+	if p := c.get("DTEND"); p != nil {
+		if end, err = d.dateTime(p, zones); err != nil {
+			return nil, fmt.Errorf("ical: VEVENT %q: %w", ev.UID, err)
+		}
+	} else if p := c.get("DURATION"); p != nil {
+		period, err := gauge.ParsePeriod(p.value)
+		if err != nil {
+			return nil, fmt.Errorf("ical: VEVENT %q: %w", ev.UID, err)
+		}
+		end = period.AddTo(begin, 1)
+	} else if ev.AllDay {
+		end = begin.AddDate(0, 0, 1)
+	}
+	if end.Before(begin) {
+		return nil, fmt.Errorf("ical: VEVENT %q: DTEND is before DTSTART", ev.UID)
+	}
+	ev.Gauge = gauge.New(begin, end)
+
+	rrules, exrules := c.all("RRULE"), c.all("EXRULE")
+	rdates, exdates := c.all("RDATE"), c.all("EXDATE")
+	if len(rrules)+len(rdates) == 0 {
+		return ev, nil
+	}
+	set := &recurrence.Set{Start: begin, Duration: end.Sub(begin)}
+	for _, p := range rrules {
+		r, err := recurrence.ParseRule(p.value, begin.Location())
+		if err != nil {
+			return nil, fmt.Errorf("ical: VEVENT %q: %w", ev.UID, err)
+		}
+		set.RRules = append(set.RRules, r)
+	}
+	for _, p := range exrules {
+		r, err := recurrence.ParseRule(p.value, begin.Location())
+		if err != nil {
+			return nil, fmt.Errorf("ical: VEVENT %q: %w", ev.UID, err)
+		}
+		set.ExRules = append(set.ExRules, r)
+	}
+	if set.RDates, err = d.dateTimes(rdates, zones, begin.Location()); err != nil {
+		return nil, fmt.Errorf("ical: VEVENT %q: %w", ev.UID, err)
+	}
+	if set.ExDates, err = d.dateTimes(exdates, zones, begin.Location()); err != nil {
+		return nil, fmt.Errorf("ical: VEVENT %q: %w", ev.UID, err)
+	}
+	if len(set.RRules) == 0 {
+		// RDATE のみの場合は DTSTART を発生日時に含める
+		set.RDates = append(set.RDates, begin)
+	}
+	ev.Recurrence = set
+	return ev, nil
+}
+
+func isDate(p *property) bool {
+	return strings.EqualFold(p.params["VALUE"], "DATE") || len(p.value) == len(dateLayout)
+}
+
+func (d *Decoder) location(p *property, zones map[string]*time.Location) (*time.Location, error) {
+	id, ok := p.params["TZID"]
+	if !ok {
+		return d.loc, nil
+	}
+	if loc, ok := zones[id]; ok {
+		return loc, nil
+	}
+	loc, err := time.LoadLocation(id)
+	if err != nil {
+		return nil, fmt.Errorf("unknown TZID %q", id)
+	}
+	return loc, nil
+}
+
+// dateTime DATE・DATE-TIME 型の値を解析する
+func (d *Decoder) dateTime(p *property, zones map[string]*time.Location) (time.Time, error) {
+	loc, err := d.location(p, zones)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parseValue(p.value, isDate(p), loc)
+}
+
+func (d *Decoder) dateTimes(props []*property, zones map[string]*time.Location, to *time.Location) ([]time.Time, error) {
+	var times []time.Time
+	for _, p := range props {
+		if strings.EqualFold(p.params["VALUE"], "PERIOD") {
+			return nil, fmt.Errorf("unsupported %s;VALUE=PERIOD", p.name)
+		}
+		loc, err := d.location(p, zones)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range strings.Split(p.value, ",") {
+			t, err := parseValue(v, isDate(p) || len(v) == len(dateLayout), loc)
+			if err != nil {
+				return nil, err
+			}
+			times = append(times, t.In(to))
+		}
+	}
+	return times, nil
+}
+
+func parseValue(v string, date bool, loc *time.Location) (time.Time, error) {
+	switch {
+	case date:
+		return time.ParseInLocation(dateLayout, v, loc)
+	case strings.HasSuffix(v, "Z"):
+		return time.Parse(utcLayout, v)
+	default:
+		return time.ParseInLocation(localLayout, v, loc)
+	}
+}
+
+// addZone VTIMEZONE のロケーションを登録する
+func addZone(zones map[string]*time.Location, c *component) error {
+	p := c.get("TZID")
+	if p == nil {
+		return fmt.Errorf("ical: VTIMEZONE: TZID is required")
+	}
+	if loc, err := time.LoadLocation(p.value); err == nil {
+		zones[p.value] = loc
+		return nil
+	}
+	// IANA のタイムゾーン名で無い場合は標準時のオフセットの固定ゾーンとする
+	for _, child := range c.children {
+		if child.name != "STANDARD" {
+			continue
+		}
+		if to := child.get("TZOFFSETTO"); to != nil {
+			offset, err := parseOffset(to.value)
+			if err != nil {
+				return fmt.Errorf("ical: VTIMEZONE %q: %w", p.value, err)
+			}
+			zones[p.value] = time.FixedZone(p.value, offset)
+		}
+	}
+	if _, ok := zones[p.value]; !ok {
+		return fmt.Errorf("ical: VTIMEZONE %q: unknown time zone", p.value)
+	}
+	return nil
+}
+
+func parseOffset(s string) (int, error) {
+	if len(s) != 5 && len(s) != 7 || s[0] != '+' && s[0] != '-' {
+		return 0, fmt.Errorf("invalid offset %q", s)
+	}
+	var h, m, sec int
+	if _, err := fmt.Sscanf(s[1:5], "%02d%02d", &h, &m); err != nil {
+		return 0, fmt.Errorf("invalid offset %q", s)
+	}
+	if len(s) == 7 {
+		if _, err := fmt.Sscanf(s[5:], "%02d", &sec); err != nil {
+			return 0, fmt.Errorf("invalid offset %q", s)
+		}
+	}
+	offset := h*3600 + m*60 + sec
+	if s[0] == '-' {
+		offset = -offset
+	}
+	return offset, nil
+}
+
+// unfold 折り返された行を連結する
+func unfold(r io.Reader) ([]string, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var lines []string
+	for sc.Scan() {
+		line := strings.TrimSuffix(sc.Text(), "\r")
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, sc.Err()
+}
+
+// parseProperty "NAME;PARAM=VALUE:value" 形式の行を解析する(引用符内の区切り文字は無視する)
+func parseProperty(line string) (*property, error) {
+	p := &property{params: make(map[string]string)}
+	quoted := false
+	start := 0
+	var key string
+	inParams := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == ';' || c == ':':
+			token := line[start:i]
+			if !inParams {
+				p.name = strings.ToUpper(token)
+				inParams = true
+			} else if key != "" {
+				p.params[key] = strings.Trim(token, `"`)
+				key = ""
+			}
+			start = i + 1
+			if c == ':' {
+				p.value = line[i+1:]
+				if p.name == "" {
+					return nil, fmt.Errorf("invalid line %q", line)
+				}
+				return p, nil
+			}
+		case c == '=' && inParams && key == "":
+			key = strings.ToUpper(line[start:i])
+			start = i + 1
+		}
+	}
+	return nil, fmt.Errorf("invalid line %q", line)
+}
@@ -0,0 +1,141 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goccha/times/pkg/gauge"
+)
+
+const sample = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Example//Shifts//EN\r\n" +
+	"BEGIN:VTIMEZONE\r\n" +
+	"TZID:Custom Tokyo\r\n" +
+	"BEGIN:STANDARD\r\n" +
+	"DTSTART:19700101T000000\r\n" +
+	"TZOFFSETFROM:+0900\r\n" +
+	"TZOFFSETTO:+0900\r\n" +
+	"END:STANDARD\r\n" +
+	"END:VTIMEZONE\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:shift-1\r\n" +
+	"DTSTART;TZID=\"Custom Tokyo\":20200401T170000\r\n" +
+	"DURATION:PT8H\r\n" +
+	"SUMMARY:Evening\\, late\r\n" +
+	"DESCRIPTION:a very long description that is folded\r\n" +
+	"  across lines\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:leave-1\r\n" +
+	"DTSTART;VALUE=DATE:20200402\r\n" +
+	"SUMMARY:Leave\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:meeting\r\n" +
+	"DTSTART;TZID=America/New_York:20200302T090000\r\n" +
+	"DTEND;TZID=America/New_York:20200302T100000\r\n" +
+	"RRULE:FREQ=WEEKLY;BYDAY=MO\r\n" +
+	"EXDATE;TZID=America/New_York:20200316T090000\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestDecode(t *testing.T) {
+	cal, err := Decode(sample)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cal.ProdID != "-//Example//Shifts//EN" || len(cal.Events) != 3 {
+		t.Fatalf("unexpected calendar %+v", cal)
+	}
+	shift := cal.Events[0]
+	if shift.Summary != "Evening, late" {
+		t.Errorf("expected=Evening, late, actual=%s", shift.Summary)
+	}
+	if shift.Description != "a very long description that is folded across lines" {
+		t.Errorf("unexpected description %q", shift.Description)
+	}
+	if actual := shift.Gauge.FormatInterval(gauge.StartEnd); actual != "2020-04-01T17:00:00+09:00/2020-04-02T01:00:00+09:00" {
+		t.Errorf("unexpected gauge %s", actual)
+	}
+	leave := cal.Events[1]
+	if !leave.AllDay || leave.Gauge.Hours() != 24 {
+		t.Errorf("expected all-day event, actual=%v %v", leave.AllDay, leave.Gauge.Hours())
+	}
+	if cal.Events[2].Recurrence == nil {
+		t.Fatal("expected recurrence")
+	}
+}
+
+func TestCalendar_Expand(t *testing.T) {
+	d := NewDecoder(strings.NewReader(sample))
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	d.SetLocation(tokyo)
+	cal, err := d.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ny, _ := time.LoadLocation("America/New_York")
+	window := gauge.New(time.Date(2020, 3, 1, 0, 0, 0, 0, ny), time.Date(2020, 4, 1, 0, 0, 0, 0, ny))
+	events := cal.Expand(window)
+	var actual []string
+	for _, ev := range events {
+		actual = append(actual, ev.UID+"@"+ev.Gauge.Begin().Format(time.RFC3339))
+	}
+	expected := []string{
+		"meeting@2020-03-02T09:00:00-05:00",
+		"meeting@2020-03-09T09:00:00-04:00",
+		"meeting@2020-03-23T09:00:00-04:00",
+		"meeting@2020-03-30T09:00:00-04:00",
+	}
+	if strings.Join(actual, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected=%v, actual=%v", expected, actual)
+	}
+
+	window = gauge.New(time.Date(2020, 4, 1, 0, 0, 0, 0, tokyo), time.Date(2020, 4, 3, 0, 0, 0, 0, tokyo))
+	events = cal.Expand(window)
+	if len(events) != 2 || events[0].UID != "shift-1" || events[1].UID != "leave-1" {
+		t.Errorf("unexpected events %v", events)
+	}
+}
+
+func TestDecode_RoundTrip(t *testing.T) {
+	cal, err := Decode(sample)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := Encode(cal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := Decode(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, ev := range decoded.Events {
+		expected := cal.Events[i]
+		if ev.UID != expected.UID || !ev.Gauge.Begin().Equal(expected.Gauge.Begin()) || !ev.Gauge.End().Equal(expected.Gauge.End()) {
+			t.Errorf("[%d] expected=%v, actual=%v", i, expected.Gauge, ev.Gauge)
+		}
+		if ev.AllDay != expected.AllDay || (ev.Recurrence == nil) != (expected.Recurrence == nil) {
+			t.Errorf("[%d] unexpected event %+v", i, ev)
+		}
+	}
+}
+
+func TestDecode_Error(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"BEGIN:VEVENT\r\nEND:VEVENT\r\n",
+		"BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:x\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+		"BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART;TZID=Nowhere:20200401T090000\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+		"BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART:20200401T090000Z\r\nDTEND:20200401T080000Z\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+		"BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nEND:VCALENDAR\r\n",
+		"BEGIN:VCALENDAR\r\ninvalid\r\nEND:VCALENDAR\r\n",
+	} {
+		if _, err := Decode(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/goccha/times/pkg/clock"
+)
+
+// maxLineOctets 折り返し前の1行の最大オクテット数
+const maxLineOctets = 75
+
+const (
+	dateLayout  = "20060102"
+	localLayout = "20060102T150405"
+	utcLayout   = "20060102T150405Z"
+)
+
+// Encoder iCalendar 形式で出力する
+type Encoder struct {
+	w     *bufio.Writer
+	clock clock.Clock
+	err   error
+}
+
+// EncoderOption Encoder のオプション
+type EncoderOption func(e *Encoder)
+
+// WithClock DTSTAMP の指定が無いイベントに出力する現在時刻を返す時計を指定する(既定はシステム時計)
+func WithClock(c clock.Clock) EncoderOption {
+	return func(e *Encoder) {
+		e.clock = clock.OrNew(c)
+	}
+}
+
+// NewEncoder iCalendar 形式で出力する Encoder を生成する
+func NewEncoder(w io.Writer, opts ...EncoderOption) *Encoder {
+	e := &Encoder{w: bufio.NewWriter(w), clock: clock.New()}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Encode カレンダーを iCalendar 形式で出力する
+//
+// イベントで使用するタイムゾーン毎に、イベントの期間の夏時間の切り替えを含む VTIMEZONE を出力する。
+func (e *Encoder) Encode(c *Calendar) error {
+	for _, ev := range c.Events {
+		if ev.Gauge == nil {
+			return fmt.Errorf("ical: event %q has no gauge", ev.UID)
+		}
+	}
+	prodID := c.ProdID
+	if prodID == "" {
+		prodID = "-//goccha//times//EN"
+	}
+	e.line("BEGIN:VCALENDAR")
+	e.line("VERSION:2.0")
+	e.line("PRODID:" + escapeText(prodID))
+	e.line("CALSCALE:GREGORIAN")
+	if c.Name != "" {
+		e.line("X-WR-CALNAME:" + escapeText(c.Name))
+	}
+	for _, z := range zones(c.Events) {
+		e.timezone(z)
+	}
+	now := e.clock.Now().UTC()
+	for _, ev := range c.Events {
+		e.event(ev, now)
+	}
+	e.line("END:VCALENDAR")
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+// Encode カレンダーを iCalendar 形式の文字列に変換する
+func Encode(c *Calendar, opts ...EncoderOption) (string, error) {
+	var sb strings.Builder
+	if err := NewEncoder(&sb, opts...).Encode(c); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+func (e *Encoder) event(ev *Event, now time.Time) {
+	e.line("BEGIN:VEVENT")
+	e.line("UID:" + escapeText(ev.UID))
+	stamp := ev.Stamp
+	if stamp.IsZero() {
+		stamp = now
+	}
+	e.line("DTSTAMP:" + stamp.UTC().Format(utcLayout))
+	if ev.AllDay {
+		begin := ev.Gauge.Begin()
+		end := ev.Gauge.End()
+		e.line("DTSTART;VALUE=DATE:" + begin.Format(dateLayout))
+		if end.After(begin) {
+			e.line("DTEND;VALUE=DATE:" + end.Format(dateLayout))
+		}
+	} else {
+		e.line(dateTimeProperty("DTSTART", ev.Gauge.Begin()))
+		e.line(dateTimeProperty("DTEND", ev.Gauge.End()))
+	}
+	if ev.Summary != "" {
+		e.line("SUMMARY:" + escapeText(ev.Summary))
+	}
+	if ev.Description != "" {
+		e.line("DESCRIPTION:" + escapeText(ev.Description))
+	}
+	if ev.Recurrence != nil {
+		for _, l := range ev.Recurrence.Lines() {
+			if !strings.HasPrefix(l, "DTSTART") && !strings.HasPrefix(l, "DURATION") {
+				e.line(l)
+			}
+		}
+	}
+	e.line("END:VEVENT")
+}
+
+func dateTimeProperty(name string, t time.Time) string {
+	if id := tzid(t.Location()); id != "" {
+		return name + ";TZID=" + id + ":" + t.Format(localLayout)
+	}
+	return name + ":" + t.UTC().Format(utcLayout)
+}
+
+// zoneRange VTIMEZONE を出力するタイムゾーンと期間
+type zoneRange struct {
+	loc      *time.Location
+	from, to int // 年
+}
+
+// zones イベントで使用するタイムゾーンを返す
+func zones(events []*Event) []*zoneRange {
+	m := make(map[string]*zoneRange)
+	add := func(t time.Time) {
+		id := tzid(t.Location())
+		if id == "" {
+			return
+		}
+		if z, ok := m[id]; ok {
+			if t.Year() < z.from {
+				z.from = t.Year()
+			}
+			if t.Year() > z.to {
+				z.to = t.Year()
+			}
+			return
+		}
+		m[id] = &zoneRange{loc: t.Location(), from: t.Year(), to: t.Year()}
+	}
+	for _, ev := range events {
+		if ev.AllDay || ev.Gauge == nil {
+			continue
+		}
+		add(ev.Gauge.Begin())
+		add(ev.Gauge.End())
+		if ev.Recurrence != nil {
+			for _, r := range ev.Recurrence.RRules {
+				if !r.Until.IsZero() {
+					add(r.Until.In(ev.Gauge.Begin().Location()))
+				} else if r.Count == 0 {
+					// 無期限の繰り返しは1年先までの切り替えを出力する
+					add(ev.Gauge.Begin().AddDate(1, 0, 0))
+				}
+			}
+			for _, d := range ev.Recurrence.RDates {
+				add(d.In(ev.Gauge.Begin().Location()))
+			}
+		}
+	}
+	ids := make([]string, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	ranges := make([]*zoneRange, len(ids))
+	for i, id := range ids {
+		ranges[i] = m[id]
+	}
+	return ranges
+}
+
+// timezone VTIMEZONE を出力する
+func (e *Encoder) timezone(z *zoneRange) {
+	e.line("BEGIN:VTIMEZONE")
+	e.line("TZID:" + z.loc.String())
+	t := time.Date(z.from, time.January, 1, 0, 0, 0, 0, z.loc)
+	end := time.Date(z.to+1, time.January, 1, 0, 0, 0, 0, z.loc)
+	// 期間開始時点のオフセット
+	name, offset := t.Zone()
+	prevOffset := offset
+	if start, _ := t.ZoneBounds(); !start.IsZero() {
+		_, prevOffset = start.Add(-time.Second).Zone()
+	}
+	e.observance(t.IsDST(), zoneStart(t), prevOffset, offset, name)
+	for {
+		_, next := t.ZoneBounds()
+		if next.IsZero() || !next.Before(end) {
+			break
+		}
+		prevOffset = offset
+		t = next
+		name, offset = t.Zone()
+		e.observance(t.IsDST(), next.Add(time.Duration(prevOffset)*time.Second).UTC(), prevOffset, offset, name)
+	}
+	e.line("END:VTIMEZONE")
+}
+
+// zoneStart 期間開始時点のゾーンの開始日時(切り替え前のオフセットでの壁時計の時刻)を返す
+func zoneStart(t time.Time) time.Time {
+	start, _ := t.ZoneBounds()
+	if start.IsZero() {
+		return time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	_, prev := start.Add(-time.Second).Zone()
+	return start.Add(time.Duration(prev) * time.Second).UTC()
+}
+
+func (e *Encoder) observance(dst bool, wall time.Time, from, to int, name string) {
+	kind := "STANDARD"
+	if dst {
+		kind = "DAYLIGHT"
+	}
+	e.line("BEGIN:" + kind)
+	e.line("DTSTART:" + wall.Format(localLayout))
+	e.line("TZOFFSETFROM:" + formatOffset(from))
+	e.line("TZOFFSETTO:" + formatOffset(to))
+	if name != "" && !strings.HasPrefix(name, "+") && !strings.HasPrefix(name, "-") {
+		e.line("TZNAME:" + escapeText(name))
+	}
+	e.line("END:" + kind)
+}
+
+func formatOffset(offset int) string {
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	s := fmt.Sprintf("%c%02d%02d", sign, offset/3600, offset%3600/60)
+	if sec := offset % 60; sec != 0 {
+		s += fmt.Sprintf("%02d", sec)
+	}
+	return s
+}
+
+// line 1行を75オクテットで折り返して出力する
+func (e *Encoder) line(s string) {
+	if e.err != nil {
+		return
+	}
+	e.err = writeFolded(e.w, s)
+}
+
+func writeFolded(w *bufio.Writer, s string) error {
+	limit := maxLineOctets
+	for len(s) > limit {
+		i := limit
+		for i > 0 && !utf8.RuneStart(s[i]) {
+			i--
+		}
+		if _, err := w.WriteString(s[:i] + "\r\n "); err != nil {
+			return err
+		}
+		s = s[i:]
+		limit = maxLineOctets - 1 // 継続行の先頭の空白
+	}
+	_, err := w.WriteString(s + "\r\n")
+	return err
+}
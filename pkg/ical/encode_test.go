@@ -0,0 +1,137 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goccha/times/pkg/clock"
+	"github.com/goccha/times/pkg/gauge"
+	"github.com/goccha/times/pkg/recurrence"
+)
+
+func TestEncode(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	stamp := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	cal := &Calendar{
+		Events: []*Event{
+			{
+				UID:         "shift-1@example.com",
+				Summary:     "Night shift; ward A, bed 3",
+				Description: "line1\nline2",
+				Gauge:       gauge.New(time.Date(2020, 3, 7, 22, 0, 0, 0, ny), time.Date(2020, 3, 8, 7, 0, 0, 0, ny)),
+				Stamp:       stamp,
+			},
+			{
+				UID:     "leave-1@example.com",
+				Summary: "Leave",
+				Gauge:   gauge.New(time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC), time.Date(2020, 4, 3, 0, 0, 0, 0, time.UTC)),
+				AllDay:  true,
+				Stamp:   stamp,
+			},
+		},
+	}
+	s, err := Encode(cal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"BEGIN:VTIMEZONE\r\nTZID:America/New_York\r\n",
+		"BEGIN:DAYLIGHT\r\nDTSTART:20200308T020000\r\nTZOFFSETFROM:-0500\r\nTZOFFSETTO:-0400\r\nTZNAME:EDT\r\nEND:DAYLIGHT\r\n",
+		"BEGIN:STANDARD\r\nDTSTART:20201101T020000\r\nTZOFFSETFROM:-0400\r\nTZOFFSETTO:-0500\r\nTZNAME:EST\r\nEND:STANDARD\r\n",
+		"DTSTART;TZID=America/New_York:20200307T220000\r\n",
+		"DTEND;TZID=America/New_York:20200308T070000\r\n",
+		`SUMMARY:Night shift\; ward A\, bed 3` + "\r\n",
+		`DESCRIPTION:line1\nline2` + "\r\n",
+		"DTSTART;VALUE=DATE:20200401\r\nDTEND;VALUE=DATE:20200403\r\n",
+		"DTSTAMP:20200101T000000Z\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(s, expected) {
+			t.Errorf("expected=%q, actual=%q", expected, s)
+		}
+	}
+}
+
+func TestEncode_Folding(t *testing.T) {
+	summary := strings.Repeat("勤務シフト", 20)
+	cal := &Calendar{Events: []*Event{{
+		UID:     "long",
+		Summary: summary,
+		Gauge:   gauge.New(time.Date(2020, 4, 1, 9, 0, 0, 0, time.UTC), time.Date(2020, 4, 1, 17, 0, 0, 0, time.UTC)),
+	}}}
+	s, err := Encode(cal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(s, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line too long (%d): %q", len(line), line)
+		}
+	}
+	decoded, err := Decode(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Events[0].Summary != summary {
+		t.Errorf("expected=%s, actual=%s", summary, decoded.Events[0].Summary)
+	}
+}
+
+func TestEncode_Recurrence(t *testing.T) {
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	begin := time.Date(2020, 4, 14, 10, 0, 0, 0, tokyo)
+	rule, _ := recurrence.ParseRule("FREQ=MONTHLY;BYDAY=2TU,4TU;COUNT=4")
+	cal := &Calendar{Events: []*Event{{
+		UID:        "meeting",
+		Gauge:      gauge.New(begin, begin.Add(time.Hour)),
+		Recurrence: &recurrence.Set{Start: begin, Duration: time.Hour, RRules: []*recurrence.Rule{rule}},
+	}}}
+	s, err := Encode(cal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"RRULE:FREQ=MONTHLY;COUNT=4;BYDAY=2TU,4TU\r\n",
+		"TZID:Asia/Tokyo\r\nBEGIN:STANDARD\r\n",
+		"TZOFFSETTO:+0900\r\n",
+	} {
+		if !strings.Contains(s, expected) {
+			t.Errorf("expected=%q, actual=%q", expected, s)
+		}
+	}
+	if strings.Contains(s, "DURATION") {
+		t.Errorf("unexpected DURATION: %q", s)
+	}
+}
+
+func TestEncode_Stamp(t *testing.T) {
+	now := time.Date(2020, 4, 1, 9, 30, 0, 0, time.UTC)
+	cal := &Calendar{Events: []*Event{
+		{UID: "a", Gauge: gauge.New(now, now.Add(time.Hour))},
+	}}
+	actual, err := Encode(cal, WithClock(clock.NewFake(now)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(actual, "DTSTAMP:20200401T093000Z\r\n") {
+		t.Errorf("unexpected DTSTAMP %s", actual)
+	}
+}
+
+func TestEncode_NoGauge(t *testing.T) {
+	now := time.Date(2020, 4, 1, 9, 30, 0, 0, time.UTC)
+	cal := &Calendar{Events: []*Event{
+		{UID: "a", Gauge: gauge.New(now, now.Add(time.Hour))},
+		{UID: "b"},
+	}}
+	var sb strings.Builder
+	err := NewEncoder(&sb).Encode(cal)
+	if err == nil || err.Error() != `ical: event "b" has no gauge` {
+		t.Errorf("unexpected error %v", err)
+	}
+	if sb.Len() != 0 {
+		t.Errorf("expected no output, actual=%q", sb.String())
+	}
+}
@@ -0,0 +1,93 @@
+package ical
+
+import (
+	"strings"
+	"time"
+
+	"github.com/goccha/times/pkg/gauge"
+	"github.com/goccha/times/pkg/recurrence"
+)
+
+// Event iCalendar の VEVENT
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Gauge       *gauge.TimeGauge // 期間(繰り返しの場合は最初の発生期間)
+	AllDay      bool             // 終日(日付のみ)のイベントかどうか
+	Stamp       time.Time        // DTSTAMP(ゼロ値の場合は出力時の Encoder の時計の現在時刻)
+	Recurrence  *recurrence.Set  // 繰り返し(RRULE・EXRULE・RDATE・EXDATE)
+}
+
+// Calendar iCalendar の VCALENDAR
+type Calendar struct {
+	ProdID string
+	Name   string // X-WR-CALNAME
+	Events []*Event
+}
+
+// Expand window と重なるイベントを返す(繰り返しイベントは発生期間毎に展開する)
+//
+// window が nil の場合は全てのイベントを返すため、繰り返しは COUNT・UNTIL で終了すること。
+func (c *Calendar) Expand(window *gauge.TimeGauge) []*Event {
+	var events []*Event
+	for _, e := range c.Events {
+		if e.Recurrence == nil {
+			if window == nil || overlaps(e.Gauge, window) {
+				events = append(events, e)
+			}
+			continue
+		}
+		for _, tg := range e.Recurrence.Between(window) {
+			occurrence := *e
+			occurrence.Gauge = tg
+			occurrence.Recurrence = nil
+			events = append(events, &occurrence)
+		}
+	}
+	return events
+}
+
+func overlaps(tg, window *gauge.TimeGauge) bool {
+	if tg.Duration() == 0 {
+		return !tg.Begin().Before(window.Begin()) && tg.Begin().Before(window.End())
+	}
+	return tg.Begin().Before(window.End()) && tg.End().After(window.Begin())
+}
+
+// escapeText TEXT 型の値をエスケープする
+func escapeText(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+// unescapeText TEXT 型の値のエスケープを解除する
+func unescapeText(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			sb.WriteByte('\n')
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String()
+}
+
+// tzid ロケーションの TZID を返す(IANA のタイムゾーン名で無い場合は空文字)
+func tzid(loc *time.Location) string {
+	if loc == time.UTC || loc == time.Local {
+		return ""
+	}
+	name := loc.String()
+	if _, err := time.LoadLocation(name); err != nil {
+		return ""
+	}
+	return name
+}
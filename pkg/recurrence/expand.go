@@ -0,0 +1,363 @@
+package recurrence
+
+import (
+	"sort"
+	"time"
+
+	"github.com/goccha/times/pkg/zone"
+)
+
+// maxYear 展開を打ち切る年
+const maxYear = 9999
+
+// ruleIterator ルールの発生日時を時系列順に生成する
+//
+// 日時の計算は壁時計の時刻(UTC で表現)で行い、生成時にルールのロケーションの日時に変換する。
+type ruleIterator struct {
+	rule     *Rule
+	start    time.Time
+	wall     time.Time
+	loc      *time.Location
+	interval int
+	p0       time.Time // 最初の期間の開始(壁時計)
+
+	byMonth    []int
+	byMonthDay []int
+	byYearDay  []int
+	byWeekNo   []int
+	byDay      []Weekday
+	byHour     []int
+	byMinute   []int
+	bySecond   []int
+
+	k     int
+	buf   []time.Time
+	count int
+	last  time.Time
+	done  bool
+}
+
+// newRuleIterator DTSTART を起点とするルールの発生日時の生成を開始する
+//
+// COUNT が指定されていない場合は from 以前の期間を読み飛ばす。
+func newRuleIterator(r *Rule, start time.Time, from time.Time) *ruleIterator {
+	it := &ruleIterator{
+		rule:       r,
+		start:      start,
+		wall:       zone.Wall(start),
+		loc:        start.Location(),
+		interval:   r.Interval,
+		byMonth:    sortedInts(r.ByMonth),
+		byMonthDay: r.ByMonthDay,
+		byYearDay:  r.ByYearDay,
+		byWeekNo:   r.ByWeekNo,
+		byDay:      r.ByDay,
+		byHour:     sortedInts(r.ByHour),
+		byMinute:   sortedInts(r.ByMinute),
+		bySecond:   sortedInts(r.BySecond),
+	}
+	if it.interval < 1 {
+		it.interval = 1
+	}
+	w := it.wall
+	if len(it.byWeekNo) == 0 && len(it.byYearDay) == 0 && len(it.byMonthDay) == 0 && len(it.byDay) == 0 {
+		switch r.Freq {
+		case Yearly:
+			if len(it.byMonth) == 0 {
+				it.byMonth = []int{int(w.Month())}
+			}
+			it.byMonthDay = []int{w.Day()}
+		case Monthly:
+			it.byMonthDay = []int{w.Day()}
+		case Weekly:
+			it.byDay = []Weekday{{Day: w.Weekday()}}
+		}
+	}
+	if len(it.byHour) == 0 && r.Freq >= Daily {
+		it.byHour = []int{w.Hour()}
+	}
+	if len(it.byMinute) == 0 && r.Freq >= Hourly {
+		it.byMinute = []int{w.Minute()}
+	}
+	if len(it.bySecond) == 0 && r.Freq >= Minutely {
+		it.bySecond = []int{w.Second()}
+	}
+
+	date := time.Date(w.Year(), w.Month(), w.Day(), 0, 0, 0, 0, time.UTC)
+	switch r.Freq {
+	case Yearly:
+		it.p0 = time.Date(w.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	case Monthly:
+		it.p0 = time.Date(w.Year(), w.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case Weekly:
+		it.p0 = r.WeekStart.Start(date)
+	case Daily:
+		it.p0 = date
+	default:
+		it.p0 = w.Truncate(it.unit())
+	}
+	if r.Count == 0 && from.After(start) {
+		if k := it.index(zone.Wall(from.In(it.loc)))/it.interval - 1; k > 0 {
+			it.k = k
+		}
+	}
+	return it
+}
+
+// unit 日未満の頻度の期間の長さ
+func (it *ruleIterator) unit() time.Duration {
+	switch it.rule.Freq {
+	case Hourly:
+		return time.Hour
+	case Minutely:
+		return time.Minute
+	default:
+		return time.Second
+	}
+}
+
+// index 指定した壁時計の時刻を含む期間の番号(間隔を考慮しない)を返す
+func (it *ruleIterator) index(w time.Time) int {
+	switch it.rule.Freq {
+	case Yearly:
+		return w.Year() - it.p0.Year()
+	case Monthly:
+		return (w.Year()-it.p0.Year())*12 + int(w.Month()) - int(it.p0.Month())
+	case Weekly:
+		return int(w.Sub(it.p0) / (7 * 24 * time.Hour))
+	case Daily:
+		return int(w.Sub(it.p0) / (24 * time.Hour))
+	default:
+		return int(w.Sub(it.p0) / it.unit())
+	}
+}
+
+// period k 番目の期間の開始(壁時計)を返す
+func (it *ruleIterator) period(k int) time.Time {
+	n := k * it.interval
+	switch it.rule.Freq {
+	case Yearly:
+		return it.p0.AddDate(n, 0, 0)
+	case Monthly:
+		return it.p0.AddDate(0, n, 0)
+	case Weekly:
+		return it.p0.AddDate(0, 0, 7*n)
+	case Daily:
+		return it.p0.AddDate(0, 0, n)
+	default:
+		return it.p0.Add(time.Duration(n) * it.unit())
+	}
+}
+
+// next 次の発生日時を返す
+func (it *ruleIterator) next() (time.Time, bool) {
+	for !it.done {
+		for len(it.buf) > 0 {
+			t := it.buf[0]
+			it.buf = it.buf[1:]
+			if t.Before(it.start) || !it.last.IsZero() && !t.After(it.last) {
+				continue
+			}
+			if !it.rule.Until.IsZero() && t.After(it.rule.Until) {
+				it.done = true
+				return time.Time{}, false
+			}
+			it.count++
+			if it.rule.Count > 0 && it.count >= it.rule.Count {
+				it.done = true
+			}
+			it.last = t
+			return t, true
+		}
+		p := it.period(it.k)
+		if p.Year() > maxYear {
+			it.done = true
+			break
+		}
+		it.k++
+		it.expand(p)
+	}
+	return time.Time{}, false
+}
+
+// expand 期間内の発生日時を生成する
+func (it *ruleIterator) expand(p time.Time) {
+	var days []time.Time
+	switch it.rule.Freq {
+	case Yearly:
+		days = dayRange(p, p.AddDate(1, 0, 0))
+	case Monthly:
+		days = dayRange(p, p.AddDate(0, 1, 0))
+	case Weekly:
+		days = dayRange(p, p.AddDate(0, 0, 7))
+	default:
+		days = []time.Time{time.Date(p.Year(), p.Month(), p.Day(), 0, 0, 0, 0, time.UTC)}
+	}
+	var candidates []time.Time
+	for _, d := range days {
+		if !it.matchDay(d) {
+			if it.rule.Freq < Daily {
+				// 日未満の頻度では翌日の期間まで読み飛ばす
+				next := d.AddDate(0, 0, 1)
+				step := time.Duration(it.interval) * it.unit()
+				it.k = int((next.Sub(it.p0) + step - 1) / step)
+			}
+			continue
+		}
+		for _, h := range it.hours(p) {
+			for _, m := range it.minutes(p) {
+				for _, s := range it.seconds(p) {
+					candidates = append(candidates, d.Add(time.Duration(h)*time.Hour+time.Duration(m)*time.Minute+time.Duration(s)*time.Second))
+				}
+			}
+		}
+	}
+	if len(it.rule.BySetPos) > 0 {
+		candidates = setPos(candidates, it.rule.BySetPos)
+	}
+	for _, c := range candidates {
+		it.buf = append(it.buf, localTime(c.Add(time.Duration(it.wall.Nanosecond())), it.loc))
+	}
+}
+
+func (it *ruleIterator) hours(p time.Time) []int {
+	if it.rule.Freq >= Daily {
+		return it.byHour
+	}
+	return filter(p.Hour(), it.byHour)
+}
+
+func (it *ruleIterator) minutes(p time.Time) []int {
+	if it.rule.Freq >= Hourly {
+		return it.byMinute
+	}
+	return filter(p.Minute(), it.byMinute)
+}
+
+func (it *ruleIterator) seconds(p time.Time) []int {
+	if it.rule.Freq >= Minutely {
+		return it.bySecond
+	}
+	return filter(p.Second(), it.bySecond)
+}
+
+// filter 値が候補に含まれる場合(候補が無い場合を含む)は値のみを返す
+func filter(v int, candidates []int) []int {
+	if len(candidates) == 0 || containsInt(candidates, v) {
+		return []int{v}
+	}
+	return nil
+}
+
+// matchDay 日付が BYMONTH・BYWEEKNO・BYYEARDAY・BYMONTHDAY・BYDAY の条件を満たすかどうか
+func (it *ruleIterator) matchDay(d time.Time) bool {
+	if len(it.byMonth) > 0 && !containsInt(it.byMonth, int(d.Month())) {
+		return false
+	}
+	if len(it.byWeekNo) > 0 {
+		y, w := it.rule.WeekStart.Week(d)
+		n := it.rule.WeekStart.WeeksInYear(y)
+		if !containsRelative(it.byWeekNo, w, n) {
+			return false
+		}
+	}
+	if len(it.byYearDay) > 0 && !containsRelative(it.byYearDay, d.YearDay(), daysInYear(d.Year())) {
+		return false
+	}
+	if len(it.byMonthDay) > 0 && !containsRelative(it.byMonthDay, d.Day(), daysInMonth(d.Year(), d.Month())) {
+		return false
+	}
+	if len(it.byDay) > 0 {
+		match := false
+		for _, wd := range it.byDay {
+			if wd.Day != d.Weekday() {
+				continue
+			}
+			if wd.N == 0 || it.nthWeekday(d, wd.N) {
+				match = true
+				break
+			}
+		}
+		if !match {
+			return false
+		}
+	}
+	return true
+}
+
+// nthWeekday 日付が第N曜日(月単位または年単位)かどうか
+func (it *ruleIterator) nthWeekday(d time.Time, n int) bool {
+	var pos, total int
+	switch {
+	case it.rule.Freq == Monthly || it.rule.Freq == Yearly && len(it.rule.ByMonth) > 0:
+		pos, total = d.Day(), daysInMonth(d.Year(), d.Month())
+	case it.rule.Freq == Yearly:
+		pos, total = d.YearDay(), daysInYear(d.Year())
+	default:
+		return true // 月・年以外の頻度では序数を無視する
+	}
+	if n > 0 {
+		return (pos-1)/7+1 == n
+	}
+	return (total-pos)/7+1 == -n
+}
+
+// setPos BYSETPOS で指定された位置の候補を選択する
+func setPos(candidates []time.Time, positions []int) []time.Time {
+	var selected []time.Time
+	for _, pos := range positions {
+		i := pos - 1
+		if pos < 0 {
+			i = len(candidates) + pos
+		}
+		if i >= 0 && i < len(candidates) {
+			selected = append(selected, candidates[i])
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool { return selected[i].Before(selected[j]) })
+	return selected
+}
+
+func dayRange(begin, end time.Time) []time.Time {
+	days := make([]time.Time, 0, 31)
+	for d := begin; d.Before(end); d = d.AddDate(0, 0, 1) {
+		days = append(days, d)
+	}
+	return days
+}
+
+func daysInYear(year int) int {
+	return time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+}
+
+func daysInMonth(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func containsInt(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// containsRelative 値が候補に含まれるかどうか(負の候補は末尾からの位置)
+func containsRelative(values []int, v, total int) bool {
+	for _, x := range values {
+		if x == v || x < 0 && total+x+1 == v {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedInts(values []int) []int {
+	if len(values) == 0 {
+		return nil
+	}
+	s := append([]int(nil), values...)
+	sort.Ints(s)
+	return s
+}
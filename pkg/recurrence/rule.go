@@ -0,0 +1,291 @@
+package recurrence
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccha/times/pkg/weeks"
+	"github.com/goccha/times/pkg/zone"
+)
+
+// Frequency 繰り返しの頻度(FREQ)
+type Frequency int
+
+const (
+	Secondly Frequency = iota
+	Minutely
+	Hourly
+	Daily
+	Weekly
+	Monthly
+	Yearly
+)
+
+var frequencyNames = []string{"SECONDLY", "MINUTELY", "HOURLY", "DAILY", "WEEKLY", "MONTHLY", "YEARLY"}
+
+// String RFC 5545 の FREQ の値を返す
+func (f Frequency) String() string {
+	if f < Secondly || f > Yearly {
+		return "Frequency(" + strconv.Itoa(int(f)) + ")"
+	}
+	return frequencyNames[f]
+}
+
+var weekdayNames = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Weekday BYDAY の要素(N が 0 以外の場合は第N曜日、負の場合は末尾から)
+type Weekday struct {
+	N   int
+	Day time.Weekday
+}
+
+// String RFC 5545 の BYDAY の要素(2TU, -1FR など)を返す
+func (w Weekday) String() string {
+	if w.N == 0 {
+		return weekdayNames[w.Day]
+	}
+	return strconv.Itoa(w.N) + weekdayNames[w.Day]
+}
+
+// Rule RFC 5545 の繰り返しルール(RRULE/EXRULE)
+type Rule struct {
+	Freq       Frequency
+	Interval   int       // 0 の場合は 1
+	Count      int       // 0 の場合は無制限
+	Until      time.Time // ゼロ値の場合は無制限
+	BySecond   []int
+	ByMinute   []int
+	ByHour     []int
+	ByDay      []Weekday
+	ByMonthDay []int
+	ByYearDay  []int
+	ByWeekNo   []int
+	ByMonth    []int
+	BySetPos   []int
+	WeekStart  weeks.Rule // WKST(ゼロ値は weeks.Sunday、ParseRule で WKST を省略した場合は RFC 5545 に従い weeks.ISO)
+}
+
+// ParseRule RFC 5545 の RRULE の値(FREQ=WEEKLY;BYDAY=TU など)を解析する
+//
+// "RRULE:" "EXRULE:" の接頭辞は省略できる。タイムゾーンの指定が無い UNTIL は loc(nil の場合は UTC)で解釈する。
+func ParseRule(s string, loc ...*time.Location) (*Rule, error) {
+	l := time.UTC
+	if len(loc) > 0 && loc[0] != nil {
+		l = loc[0]
+	}
+	orig := s
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, ':'); i >= 0 {
+		switch strings.ToUpper(s[:i]) {
+		case "RRULE", "EXRULE":
+			s = s[i+1:]
+		}
+	}
+	r := &Rule{WeekStart: weeks.ISO}
+	freq := false
+	for _, part := range strings.Split(s, ";") {
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return nil, fmt.Errorf("recurrence: invalid rule %q: %q", orig, part)
+		}
+		key, value := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+		var err error
+		switch key {
+		case "FREQ":
+			freq = true
+			r.Freq, err = parseFrequency(value)
+		case "INTERVAL":
+			r.Interval, err = parsePositive(value)
+		case "COUNT":
+			r.Count, err = parsePositive(value)
+		case "UNTIL":
+			r.Until, err = parseDateTime(value, l)
+		case "BYSECOND":
+			r.BySecond, err = parseInts(value, 0, 60, false)
+		case "BYMINUTE":
+			r.ByMinute, err = parseInts(value, 0, 59, false)
+		case "BYHOUR":
+			r.ByHour, err = parseInts(value, 0, 23, false)
+		case "BYDAY":
+			r.ByDay, err = parseWeekdays(value)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseInts(value, 1, 31, true)
+		case "BYYEARDAY":
+			r.ByYearDay, err = parseInts(value, 1, 366, true)
+		case "BYWEEKNO":
+			r.ByWeekNo, err = parseInts(value, 1, 53, true)
+		case "BYMONTH":
+			r.ByMonth, err = parseInts(value, 1, 12, false)
+		case "BYSETPOS":
+			r.BySetPos, err = parseInts(value, 1, 366, true)
+		case "WKST":
+			var d time.Weekday
+			d, err = parseWeekday(value)
+			r.WeekStart = weeks.Rule(d)
+		default:
+			err = fmt.Errorf("unknown part %q", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("recurrence: invalid rule %q: %w", orig, err)
+		}
+	}
+	if !freq {
+		return nil, fmt.Errorf("recurrence: invalid rule %q: FREQ is required", orig)
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return nil, fmt.Errorf("recurrence: invalid rule %q: COUNT and UNTIL are exclusive", orig)
+	}
+	return r, nil
+}
+
+// String RFC 5545 の RRULE の値を返す
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq.String()}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(utcLayout))
+	}
+	parts = appendInts(parts, "BYSECOND", r.BySecond)
+	parts = appendInts(parts, "BYMINUTE", r.ByMinute)
+	parts = appendInts(parts, "BYHOUR", r.ByHour)
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			days[i] = d.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	parts = appendInts(parts, "BYMONTHDAY", r.ByMonthDay)
+	parts = appendInts(parts, "BYYEARDAY", r.ByYearDay)
+	parts = appendInts(parts, "BYWEEKNO", r.ByWeekNo)
+	parts = appendInts(parts, "BYMONTH", r.ByMonth)
+	parts = appendInts(parts, "BYSETPOS", r.BySetPos)
+	if r.WeekStart != weeks.ISO {
+		parts = append(parts, "WKST="+weekdayNames[r.WeekStart])
+	}
+	return strings.Join(parts, ";")
+}
+
+func appendInts(parts []string, key string, values []int) []string {
+	if len(values) == 0 {
+		return parts
+	}
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = strconv.Itoa(v)
+	}
+	return append(parts, key+"="+strings.Join(s, ","))
+}
+
+func parseFrequency(s string) (Frequency, error) {
+	for i, name := range frequencyNames {
+		if s == name {
+			return Frequency(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown FREQ %q", s)
+}
+
+func parsePositive(s string) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil || v < 1 {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return v, nil
+}
+
+// parseInts カンマ区切りの数値を解析する(negative が true の場合は負の値も許可する)
+func parseInts(s string, min, max int, negative bool) ([]int, error) {
+	var values []int
+	for _, v := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", v)
+		}
+		abs := n
+		if negative && n < 0 {
+			abs = -n
+		}
+		if abs < min || abs > max {
+			return nil, fmt.Errorf("number %d out of range", n)
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
+
+func parseWeekday(s string) (time.Weekday, error) {
+	for i, name := range weekdayNames {
+		if s == name {
+			return time.Weekday(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown weekday %q", s)
+}
+
+func parseWeekdays(s string) ([]Weekday, error) {
+	var days []Weekday
+	for _, v := range strings.Split(s, ",") {
+		if len(v) < 2 {
+			return nil, fmt.Errorf("invalid weekday %q", v)
+		}
+		d, err := parseWeekday(v[len(v)-2:])
+		if err != nil {
+			return nil, err
+		}
+		w := Weekday{Day: d}
+		if n := v[:len(v)-2]; n != "" {
+			if w.N, err = strconv.Atoi(n); err != nil || w.N == 0 || w.N < -53 || w.N > 53 {
+				return nil, fmt.Errorf("invalid weekday %q", v)
+			}
+		}
+		days = append(days, w)
+	}
+	return days, nil
+}
+
+const (
+	dateLayout  = "20060102"
+	localLayout = "20060102T150405"
+	utcLayout   = "20060102T150405Z"
+)
+
+// parseDateTime RFC 5545 の DATE・DATE-TIME を解析する(UTC 以外は loc の時刻として解釈する)
+func parseDateTime(s string, loc *time.Location) (time.Time, error) {
+	switch {
+	case len(s) == len(utcLayout) && strings.HasSuffix(s, "Z"):
+		return time.Parse(utcLayout, s)
+	case len(s) == len(localLayout):
+		t, err := time.Parse(localLayout, s)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return localTime(t, loc), nil
+	case len(s) == len(dateLayout):
+		t, err := time.Parse(dateLayout, s)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return localTime(t, loc), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date-time %q", s)
+}
+
+// localTime 時刻(UTC で表現した壁時計の時刻)を指定したロケーションの日時に変換する
+//
+// RFC 5545 に従い、夏時間の切り替えで存在しない時刻は切り替え前のオフセットで解釈し(時刻を進める)、
+// 重複する時刻は早い方を選択する。
+func localTime(wall time.Time, loc *time.Location) time.Time {
+	t, _ := zone.Resolve(wall, loc, zone.Compatible)
+	return t
+}
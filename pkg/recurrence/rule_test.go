@@ -0,0 +1,93 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goccha/times/pkg/weeks"
+)
+
+func TestParseRule(t *testing.T) {
+	r, err := ParseRule("RRULE:FREQ=MONTHLY;INTERVAL=2;BYDAY=2TU,-1FR;BYHOUR=10;WKST=SU")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Freq != Monthly || r.Interval != 2 || r.WeekStart != weeks.Sunday {
+		t.Errorf("unexpected rule %+v", r)
+	}
+	if len(r.ByDay) != 2 || r.ByDay[0] != (Weekday{N: 2, Day: time.Tuesday}) || r.ByDay[1] != (Weekday{N: -1, Day: time.Friday}) {
+		t.Errorf("unexpected BYDAY %v", r.ByDay)
+	}
+	expected := "FREQ=MONTHLY;INTERVAL=2;BYHOUR=10;BYDAY=2TU,-1FR;WKST=SU"
+	if actual := r.String(); actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+
+	r, err = ParseRule("freq=weekly;until=20201231T235959Z;byday=mo,tu,we,th,fr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "FREQ=WEEKLY;UNTIL=20201231T235959Z;BYDAY=MO,TU,WE,TH,FR"
+	if actual := r.String(); actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+	if r.WeekStart != weeks.ISO {
+		t.Errorf("expected=%v, actual=%v", weeks.ISO, r.WeekStart)
+	}
+	// 構造体リテラルの WeekStart のゼロ値は日曜
+	expected = "FREQ=WEEKLY;WKST=SU"
+	if actual := (&Rule{Freq: Weekly}).String(); actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+	loc, _ := time.LoadLocation("Asia/Tokyo")
+	r, err = ParseRule("FREQ=DAILY;UNTIL=20201231T180000", loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "2020-12-31T09:00:00Z"; r.Until.UTC().Format(time.RFC3339) != expected {
+		t.Errorf("expected=%s, actual=%s", expected, r.Until.UTC().Format(time.RFC3339))
+	}
+}
+
+func TestParseRule_Error(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=FORTNIGHTLY",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20201231T000000Z",
+		"FREQ=DAILY;BYHOUR=24",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=MONTHLY;BYDAY=0MO",
+		"FREQ=MONTHLY;BYDAY=XX",
+		"FREQ=DAILY;FOO=1",
+		"FREQ=DAILY;UNTIL=2020",
+	} {
+		if _, err := ParseRule(s); err == nil {
+			t.Errorf("%s: expected error", s)
+		}
+	}
+}
+
+func TestLocalTime(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	london, _ := time.LoadLocation("Europe/London")
+	tests := []struct {
+		wall     string
+		loc      *time.Location
+		expected string
+	}{
+		{"2020-03-08T02:30:00Z", ny, "2020-03-08T03:30:00-04:00"},
+		{"2020-11-01T01:30:00Z", ny, "2020-11-01T01:30:00-04:00"},
+		{"2020-03-29T01:30:00Z", london, "2020-03-29T02:30:00+01:00"},
+		{"2020-10-25T01:30:00Z", london, "2020-10-25T01:30:00+01:00"},
+		{"2020-07-01T09:00:00Z", ny, "2020-07-01T09:00:00-04:00"},
+	}
+	for _, tt := range tests {
+		wall, _ := time.Parse(time.RFC3339, tt.wall)
+		actual := localTime(wall, tt.loc).Format(time.RFC3339)
+		if actual != tt.expected {
+			t.Errorf("expected=%s, actual=%s", tt.expected, actual)
+		}
+	}
+}
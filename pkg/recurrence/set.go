@@ -0,0 +1,332 @@
+package recurrence
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goccha/times/pkg/gauge"
+)
+
+// Set 繰り返しの集合(DTSTART・RRULE・EXRULE・RDATE・EXDATE)
+//
+// 発生日時は Start のロケーションで計算され、夏時間の切り替えでも壁時計の時刻が維持される。
+type Set struct {
+	Start    time.Time     // DTSTART(RRULE が無い場合は唯一の発生日時)
+	Duration time.Duration // 1回の期間(DURATION)
+	RRules   []*Rule
+	ExRules  []*Rule
+	RDates   []time.Time
+	ExDates  []time.Time
+}
+
+// Parse RFC 5545 の繰り返しの定義(DTSTART・DTEND・DURATION・RRULE・EXRULE・RDATE・EXDATE の各行)を解析する
+func Parse(s string) (*Set, error) {
+	set := &Set{}
+	var end time.Time
+	var rrules, exrules []string
+	loc := time.UTC
+	lines := strings.FieldsFunc(s, func(r rune) bool { return r == '\n' || r == '\r' })
+	// DTSTART のロケーションを先に確定する
+	for _, line := range lines {
+		name, params, value, err := splitProperty(line)
+		if err != nil {
+			return nil, err
+		}
+		if name != "DTSTART" {
+			continue
+		}
+		if loc, err = paramLocation(params, time.UTC); err != nil {
+			return nil, err
+		}
+		if set.Start, err = parseDateTime(value, loc); err != nil {
+			return nil, fmt.Errorf("recurrence: invalid DTSTART %q: %w", value, err)
+		}
+		set.Start = set.Start.In(loc)
+	}
+	if set.Start.IsZero() {
+		return nil, fmt.Errorf("recurrence: DTSTART is required")
+	}
+	for _, line := range lines {
+		name, params, value, _ := splitProperty(line)
+		switch name {
+		case "DTSTART":
+		case "DTEND":
+			l, err := paramLocation(params, loc)
+			if err != nil {
+				return nil, err
+			}
+			if end, err = parseDateTime(value, l); err != nil {
+				return nil, fmt.Errorf("recurrence: invalid DTEND %q: %w", value, err)
+			}
+		case "DURATION":
+			p, err := gauge.ParsePeriod(value)
+			if err != nil {
+				return nil, fmt.Errorf("recurrence: invalid DURATION %q: %w", value, err)
+			}
+			set.Duration = p.AddTo(set.Start, 1).Sub(set.Start)
+		case "RRULE":
+			rrules = append(rrules, value)
+		case "EXRULE":
+			exrules = append(exrules, value)
+		case "RDATE", "EXDATE":
+			l, err := paramLocation(params, loc)
+			if err != nil {
+				return nil, err
+			}
+			for _, v := range strings.Split(value, ",") {
+				t, err := parseDateTime(v, l)
+				if err != nil {
+					return nil, fmt.Errorf("recurrence: invalid %s %q: %w", name, v, err)
+				}
+				if name == "RDATE" {
+					set.RDates = append(set.RDates, t.In(loc))
+				} else {
+					set.ExDates = append(set.ExDates, t.In(loc))
+				}
+			}
+		default:
+			return nil, fmt.Errorf("recurrence: unsupported property %q", name)
+		}
+	}
+	if !end.IsZero() {
+		set.Duration = end.Sub(set.Start)
+	}
+	for _, v := range rrules {
+		r, err := ParseRule(v, loc)
+		if err != nil {
+			return nil, err
+		}
+		set.RRules = append(set.RRules, r)
+	}
+	for _, v := range exrules {
+		r, err := ParseRule(v, loc)
+		if err != nil {
+			return nil, err
+		}
+		set.ExRules = append(set.ExRules, r)
+	}
+	return set, nil
+}
+
+// splitProperty "NAME;PARAM=VALUE:value" 形式の行を分割する
+func splitProperty(line string) (name string, params map[string]string, value string, err error) {
+	i := strings.IndexByte(line, ':')
+	if i < 0 {
+		return "", nil, "", fmt.Errorf("recurrence: invalid line %q", line)
+	}
+	head, value := line[:i], strings.TrimSpace(line[i+1:])
+	parts := strings.Split(head, ";")
+	name = strings.ToUpper(strings.TrimSpace(parts[0]))
+	params = make(map[string]string, len(parts)-1)
+	for _, p := range parts[1:] {
+		kv := strings.SplitN(p, "=", 2)
+		if len(kv) == 2 {
+			params[strings.ToUpper(kv[0])] = strings.Trim(kv[1], `"`)
+		}
+	}
+	return name, params, value, nil
+}
+
+func paramLocation(params map[string]string, def *time.Location) (*time.Location, error) {
+	tzid, ok := params["TZID"]
+	if !ok {
+		return def, nil
+	}
+	loc, err := time.LoadLocation(tzid)
+	if err != nil {
+		return nil, fmt.Errorf("recurrence: unknown TZID %q: %w", tzid, err)
+	}
+	return loc, nil
+}
+
+// String RFC 5545 の繰り返しの定義(改行区切り)を返す
+func (s *Set) String() string {
+	return strings.Join(s.Lines(), "\r\n")
+}
+
+// Lines RFC 5545 の繰り返しの定義を行毎に返す
+func (s *Set) Lines() []string {
+	loc := s.Start.Location()
+	lines := []string{formatProperty("DTSTART", []time.Time{s.Start}, loc)}
+	if s.Duration > 0 {
+		lines = append(lines, "DURATION:"+gauge.FormatDuration(s.Duration))
+	}
+	for _, r := range s.RRules {
+		lines = append(lines, "RRULE:"+r.String())
+	}
+	for _, r := range s.ExRules {
+		lines = append(lines, "EXRULE:"+r.String())
+	}
+	if len(s.RDates) > 0 {
+		lines = append(lines, formatProperty("RDATE", s.RDates, loc))
+	}
+	if len(s.ExDates) > 0 {
+		lines = append(lines, formatProperty("EXDATE", s.ExDates, loc))
+	}
+	return lines
+}
+
+func formatProperty(name string, times []time.Time, loc *time.Location) string {
+	values := make([]string, len(times))
+	for i, t := range times {
+		if loc == time.UTC {
+			values[i] = t.UTC().Format(utcLayout)
+		} else {
+			values[i] = t.In(loc).Format(localLayout)
+		}
+	}
+	if loc == time.UTC {
+		return name + ":" + strings.Join(values, ",")
+	}
+	return name + ";TZID=" + loc.String() + ":" + strings.Join(values, ",")
+}
+
+// Iterator window と重なる発生期間を順に返すイテレータを生成する(window が nil の場合は全ての発生期間)
+func (s *Set) Iterator(window *gauge.TimeGauge) *Iterator {
+	it := &Iterator{set: s, window: window}
+	from := s.Start
+	if window != nil {
+		from = window.Begin().Add(-s.Duration)
+	}
+	for _, r := range s.RRules {
+		it.rrules = append(it.rrules, newPeekIterator(newRuleIterator(r, s.Start, from)))
+	}
+	for _, r := range s.ExRules {
+		it.exrules = append(it.exrules, newPeekIterator(newRuleIterator(r, s.Start, from)))
+	}
+	it.rdates = append([]time.Time(nil), s.RDates...)
+	if len(s.RRules) == 0 {
+		it.rdates = append(it.rdates, s.Start)
+	}
+	sort.Slice(it.rdates, func(i, j int) bool { return it.rdates[i].Before(it.rdates[j]) })
+	it.exdates = append([]time.Time(nil), s.ExDates...)
+	return it
+}
+
+// Between window と重なる発生期間を返す
+//
+// window が nil の場合は全ての発生期間を返すため、COUNT・UNTIL で終了するルールであること。
+func (s *Set) Between(window *gauge.TimeGauge) []*gauge.TimeGauge {
+	var times []*gauge.TimeGauge
+	it := s.Iterator(window)
+	for {
+		tg, ok := it.Next()
+		if !ok {
+			return times
+		}
+		times = append(times, tg)
+	}
+}
+
+// Iterator 発生期間を遅延評価で順に返すイテレータ
+type Iterator struct {
+	set     *Set
+	window  *gauge.TimeGauge
+	rrules  []*peekIterator
+	exrules []*peekIterator
+	rdates  []time.Time
+	exdates []time.Time
+	last    time.Time
+	started bool
+}
+
+// Next 次の発生期間を返す(全て返した場合は false)
+func (it *Iterator) Next() (*gauge.TimeGauge, bool) {
+	for {
+		t, ok := it.nextStart()
+		if !ok {
+			return nil, false
+		}
+		if it.excluded(t) {
+			continue
+		}
+		tg := gauge.New(t, t.Add(it.set.Duration))
+		if it.window == nil {
+			return tg, true
+		}
+		if !t.Before(it.window.End()) {
+			return nil, false
+		}
+		if tg.End().After(it.window.Begin()) || t.Equal(it.window.Begin()) {
+			return tg, true
+		}
+	}
+}
+
+// nextStart RRULE・RDATE から次の開始日時を重複を除いて返す
+func (it *Iterator) nextStart() (time.Time, bool) {
+	for {
+		var min time.Time
+		found := false
+		for _, r := range it.rrules {
+			if t, ok := r.peek(); ok && (!found || t.Before(min)) {
+				min, found = t, true
+			}
+		}
+		if len(it.rdates) > 0 && (!found || it.rdates[0].Before(min)) {
+			min, found = it.rdates[0], true
+		}
+		if !found {
+			return time.Time{}, false
+		}
+		for _, r := range it.rrules {
+			if t, ok := r.peek(); ok && t.Equal(min) {
+				r.pop()
+			}
+		}
+		for len(it.rdates) > 0 && it.rdates[0].Equal(min) {
+			it.rdates = it.rdates[1:]
+		}
+		if it.started && !min.After(it.last) {
+			continue
+		}
+		it.started = true
+		it.last = min
+		return min, true
+	}
+}
+
+// excluded EXRULE・EXDATE で除外される日時かどうか
+func (it *Iterator) excluded(t time.Time) bool {
+	for _, d := range it.exdates {
+		if d.Equal(t) {
+			return true
+		}
+	}
+	for _, r := range it.exrules {
+		for {
+			v, ok := r.peek()
+			if !ok || !v.Before(t) {
+				break
+			}
+			r.pop()
+		}
+		if v, ok := r.peek(); ok && v.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// peekIterator 先読み可能なルールのイテレータ
+type peekIterator struct {
+	it    *ruleIterator
+	head  time.Time
+	valid bool
+}
+
+func newPeekIterator(it *ruleIterator) *peekIterator {
+	p := &peekIterator{it: it}
+	p.pop()
+	return p
+}
+
+func (p *peekIterator) peek() (time.Time, bool) {
+	return p.head, p.valid
+}
+
+func (p *peekIterator) pop() {
+	p.head, p.valid = p.it.next()
+}
@@ -0,0 +1,182 @@
+package recurrence
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goccha/times/pkg/gauge"
+)
+
+func starts(times []*gauge.TimeGauge, layout string) []string {
+	s := make([]string, len(times))
+	for i, tg := range times {
+		s[i] = tg.Begin().Format(layout)
+	}
+	return s
+}
+
+func assertStarts(t *testing.T, times []*gauge.TimeGauge, layout string, expected ...string) {
+	t.Helper()
+	actual := starts(times, layout)
+	if strings.Join(actual, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected=%v, actual=%v", expected, actual)
+	}
+}
+
+func window(begin, end string) *gauge.TimeGauge {
+	b, _ := time.Parse(time.RFC3339, begin)
+	e, _ := time.Parse(time.RFC3339, end)
+	return gauge.New(b, e)
+}
+
+func TestSet_NthWeekday(t *testing.T) {
+	set, err := Parse("DTSTART;TZID=Asia/Tokyo:20200414T100000\nDTEND;TZID=Asia/Tokyo:20200414T110000\nRRULE:FREQ=MONTHLY;BYDAY=2TU,4TU")
+	if err != nil {
+		t.Fatal(err)
+	}
+	times := set.Between(window("2020-04-01T00:00:00+09:00", "2020-06-01T00:00:00+09:00"))
+	assertStarts(t, times, time.RFC3339,
+		"2020-04-14T10:00:00+09:00", "2020-04-28T10:00:00+09:00", "2020-05-12T10:00:00+09:00", "2020-05-26T10:00:00+09:00")
+	if times[0].Duration() != time.Hour {
+		t.Errorf("expected=%v, actual=%v", time.Hour, times[0].Duration())
+	}
+}
+
+func TestSet_WeekdaysUntil(t *testing.T) {
+	set, err := Parse("DTSTART;TZID=Asia/Tokyo:20200401T090000\nDURATION:PT8H\nRRULE:FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR;UNTIL=20201231T235959Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	times := set.Between(window("2020-12-01T00:00:00+09:00", "2021-02-01T00:00:00+09:00"))
+	if len(times) != 23 {
+		t.Errorf("expected=23, actual=%d", len(times))
+	}
+	if actual := times[len(times)-1].Begin().Format(time.RFC3339); actual != "2020-12-31T09:00:00+09:00" {
+		t.Errorf("expected=2020-12-31T09:00:00+09:00, actual=%s", actual)
+	}
+}
+
+func TestSet_RFC5545(t *testing.T) {
+	tests := []struct {
+		rule     string
+		start    string
+		expected []string
+	}{
+		{"FREQ=WEEKLY;INTERVAL=2;COUNT=8;WKST=SU;BYDAY=TU,TH", "19970902T090000",
+			[]string{"1997-09-02", "1997-09-04", "1997-09-16", "1997-09-18", "1997-09-30", "1997-10-02", "1997-10-14", "1997-10-16"}},
+		{"FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=MO", "19970805T090000",
+			[]string{"1997-08-05", "1997-08-10", "1997-08-19", "1997-08-24"}},
+		{"FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=SU", "19970805T090000",
+			[]string{"1997-08-05", "1997-08-17", "1997-08-19", "1997-08-31"}},
+		{"FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1;COUNT=7", "19970929T090000",
+			[]string{"1997-09-30", "1997-10-31", "1997-11-28", "1997-12-31", "1998-01-30", "1998-02-27", "1998-03-31"}},
+		{"FREQ=YEARLY;BYWEEKNO=20;BYDAY=MO;COUNT=3", "19970512T090000",
+			[]string{"1997-05-12", "1998-05-11", "1999-05-17"}},
+		{"FREQ=MONTHLY;BYMONTHDAY=-3;COUNT=3", "19970928T090000",
+			[]string{"1997-09-28", "1997-10-29", "1997-11-28"}},
+		{"FREQ=YEARLY;INTERVAL=3;COUNT=5;BYYEARDAY=1,100,200", "19970101T090000",
+			[]string{"1997-01-01", "1997-04-10", "1997-07-19", "2000-01-01", "2000-04-09"}},
+		{"FREQ=YEARLY;BYDAY=20MO;COUNT=3", "19970519T090000",
+			[]string{"1997-05-19", "1998-05-18", "1999-05-17"}},
+		{"FREQ=YEARLY;BYMONTH=3;BYDAY=TH;COUNT=4", "19970313T090000",
+			[]string{"1997-03-13", "1997-03-20", "1997-03-27", "1998-03-05"}},
+		{"FREQ=MONTHLY;COUNT=4", "20200131T090000",
+			[]string{"2020-01-31", "2020-03-31", "2020-05-31", "2020-07-31"}},
+		{"FREQ=YEARLY;COUNT=3", "20200229T090000",
+			[]string{"2020-02-29", "2024-02-29", "2028-02-29"}},
+		{"FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13;COUNT=4", "19970902T090000",
+			[]string{"1998-02-13", "1998-03-13", "1998-11-13", "1999-08-13"}},
+	}
+	for _, tt := range tests {
+		set, err := Parse("DTSTART;TZID=America/New_York:" + tt.start + "\nRRULE:" + tt.rule)
+		if err != nil {
+			t.Errorf("%s: %v", tt.rule, err)
+			continue
+		}
+		times := set.Between(nil)
+		actual := starts(times, time.DateOnly)
+		if strings.Join(actual, ",") != strings.Join(tt.expected, ",") {
+			t.Errorf("%s: expected=%v, actual=%v", tt.rule, tt.expected, actual)
+		}
+	}
+}
+
+func TestSet_Hourly(t *testing.T) {
+	set, err := Parse("DTSTART:19970902T090000Z\nRRULE:FREQ=MINUTELY;INTERVAL=20;BYHOUR=9,10;COUNT=7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertStarts(t, set.Between(nil), "2006-01-02T15:04",
+		"1997-09-02T09:00", "1997-09-02T09:20", "1997-09-02T09:40", "1997-09-02T10:00", "1997-09-02T10:20", "1997-09-02T10:40", "1997-09-03T09:00")
+}
+
+func TestSet_DST(t *testing.T) {
+	set, err := Parse("DTSTART;TZID=America/New_York:20200306T023000\nDURATION:PT1H\nRRULE:FREQ=DAILY;COUNT=4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertStarts(t, set.Between(nil), time.RFC3339,
+		"2020-03-06T02:30:00-05:00", "2020-03-07T02:30:00-05:00", "2020-03-08T03:30:00-04:00", "2020-03-09T02:30:00-04:00")
+
+	set, err = Parse("DTSTART;TZID=Europe/London:20201023T013000\nRRULE:FREQ=DAILY;COUNT=4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertStarts(t, set.Between(nil), time.RFC3339,
+		"2020-10-23T01:30:00+01:00", "2020-10-24T01:30:00+01:00", "2020-10-25T01:30:00+01:00", "2020-10-26T01:30:00Z")
+}
+
+func TestSet_Exclusions(t *testing.T) {
+	set, err := Parse(strings.Join([]string{
+		"DTSTART;TZID=Asia/Tokyo:20200401T090000",
+		"RRULE:FREQ=DAILY;COUNT=10",
+		"EXRULE:FREQ=WEEKLY;BYDAY=SA,SU",
+		"EXDATE;TZID=Asia/Tokyo:20200403T090000",
+		"RDATE;TZID=Asia/Tokyo:20200405T130000,20200501T090000",
+	}, "\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertStarts(t, set.Between(nil), "01-02T15",
+		"04-01T09", "04-02T09", "04-05T13", "04-06T09", "04-07T09", "04-08T09", "04-09T09", "04-10T09", "05-01T09")
+}
+
+func TestSet_Window(t *testing.T) {
+	set, err := Parse("DTSTART;TZID=Asia/Tokyo:20000101T220000\nDURATION:PT8H\nRRULE:FREQ=DAILY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	it := set.Iterator(window("2020-04-01T00:00:00+09:00", "2020-04-03T00:00:00+09:00"))
+	var times []*gauge.TimeGauge
+	for {
+		tg, ok := it.Next()
+		if !ok {
+			break
+		}
+		times = append(times, tg)
+	}
+	assertStarts(t, times, time.RFC3339, "2020-03-31T22:00:00+09:00", "2020-04-01T22:00:00+09:00", "2020-04-02T22:00:00+09:00")
+}
+
+func TestSet_String(t *testing.T) {
+	s := strings.Join([]string{
+		"DTSTART;TZID=Asia/Tokyo:20200401T090000",
+		"DURATION:PT1H30M",
+		"RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH;WKST=SU",
+		"EXDATE;TZID=Asia/Tokyo:20200409T090000",
+	}, "\r\n")
+	set, err := Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := set.String(); actual != s {
+		t.Errorf("expected=%q, actual=%q", s, actual)
+	}
+	if _, err = Parse("RRULE:FREQ=DAILY"); err == nil {
+		t.Error("expected error")
+	}
+	if _, err = Parse("DTSTART;TZID=Nowhere/City:20200401T090000"); err == nil {
+		t.Error("expected error")
+	}
+}
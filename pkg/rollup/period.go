@@ -0,0 +1,57 @@
+package rollup
+
+import (
+	"time"
+
+	"github.com/goccha/times/pkg/civil"
+	"github.com/goccha/times/pkg/gauge"
+	"github.com/goccha/times/pkg/weeks"
+)
+
+// Period 集計期間の区切り方
+type Period struct {
+	unit     gauge.Unit
+	boundary civil.TimeOfDay // 日の区切り時刻
+	rule     weeks.Rule      // 週の開始曜日
+	start    time.Month      // 期の開始月
+}
+
+// Daily 日毎の集計期間(boundary から翌日の boundary まで)
+func Daily(boundary civil.TimeOfDay) Period {
+	return Period{unit: gauge.Day, boundary: boundary}
+}
+
+// Weekly 週毎の集計期間
+func Weekly(rule weeks.Rule) Period {
+	return Period{unit: gauge.Week, rule: rule}
+}
+
+// Monthly 月毎の集計期間
+func Monthly() Period {
+	return Period{unit: gauge.Month, start: time.January}
+}
+
+// Fiscal start 月から始まる months ヶ月毎の集計期間(4月始まりの年度は Fiscal(time.April, 12)、四半期は Fiscal(time.April, 3))
+//
+// months は 1, 3, 12 のいずれかで、それ以外は 12 とする。
+func Fiscal(start time.Month, months int) Period {
+	p := Period{unit: gauge.Year, start: start}
+	switch months {
+	case 1:
+		p.unit = gauge.Month
+	case 3:
+		p.unit = gauge.Quarter
+	}
+	return p
+}
+
+// WithBoundary 日の区切り時刻を指定した集計期間を返す(既定は0時)
+func (p Period) WithBoundary(boundary civil.TimeOfDay) Period {
+	p.boundary = boundary
+	return p
+}
+
+// options 集計期間の区切り方を gauge.StartOf, gauge.EndOf のオプションに変換する
+func (p Period) options() []gauge.UnitOption {
+	return []gauge.UnitOption{gauge.WithDayBoundary(p.boundary), gauge.WithWeekRule(p.rule), gauge.WithYearStart(p.start)}
+}
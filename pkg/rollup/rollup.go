@@ -0,0 +1,88 @@
+package rollup
+
+import (
+	"sort"
+	"time"
+
+	"github.com/goccha/times/pkg/civil"
+	"github.com/goccha/times/pkg/gauge"
+)
+
+// Bucket 集計期間毎の集計結果
+type Bucket struct {
+	Date     civil.Date         // 集計期間の開始日
+	Gauge    *gauge.TimeGauge   // 集計期間
+	Total    time.Duration      // 集計期間に含まれる時間の合計
+	Segments []*gauge.TimeGauge // 集計期間で区切った期間(開始日時の順)
+}
+
+// Buckets 期間を集計期間毎に集計する
+//
+// 最初の期間の開始日時から最後の期間の終了日時までの集計期間を、期間を含まない集計期間も含めて順に返す。
+func Buckets(gauges []*gauge.TimeGauge, p Period, loc *time.Location) []*Bucket {
+	var begin, end time.Time
+	for _, g := range gauges {
+		if !g.End().After(g.Begin()) {
+			continue
+		}
+		if begin.IsZero() || g.Begin().Before(begin) {
+			begin = g.Begin()
+		}
+		if end.IsZero() || g.End().After(end) {
+			end = g.End()
+		}
+	}
+	if begin.IsZero() {
+		return []*Bucket{}
+	}
+	return BucketsWithin(gauges, p, loc, gauge.New(begin, end))
+}
+
+// BucketsWithin window と重なる集計期間毎に期間を集計する(window 外の時間は集計しない)
+func BucketsWithin(gauges []*gauge.TimeGauge, p Period, loc *time.Location, window *gauge.TimeGauge) []*Bucket {
+	buckets := make([]*Bucket, 0)
+	if !window.End().After(window.Begin()) {
+		return buckets
+	}
+	opts := p.options()
+	for begin := gauge.StartOf(window.Begin().In(loc), p.unit, opts...); ; {
+		end := gauge.EndOf(begin, p.unit, opts...)
+		buckets = append(buckets, &Bucket{Date: civil.DateOf(begin), Gauge: gauge.New(begin, end)})
+		if !end.Before(window.End()) {
+			break
+		}
+		begin = end
+	}
+	sorted := make([]*gauge.TimeGauge, len(gauges))
+	copy(sorted, gauges)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Begin().Before(sorted[j].Begin())
+	})
+	for _, g := range sorted {
+		begin, end := clip(g.Begin(), g.End(), window)
+		if !end.After(begin) {
+			continue
+		}
+		i := sort.Search(len(buckets), func(i int) bool {
+			return buckets[i].Gauge.End().After(begin)
+		})
+		for ; i < len(buckets) && buckets[i].Gauge.Begin().Before(end); i++ {
+			b := buckets[i]
+			s, e := clip(begin, end, b.Gauge)
+			b.Segments = append(b.Segments, gauge.New(s, e))
+			b.Total += e.Sub(s)
+		}
+	}
+	return buckets
+}
+
+// clip 期間を window の範囲に切り詰める
+func clip(begin, end time.Time, window *gauge.TimeGauge) (time.Time, time.Time) {
+	if begin.Before(window.Begin()) {
+		begin = window.Begin()
+	}
+	if end.After(window.End()) {
+		end = window.End()
+	}
+	return begin, end
+}
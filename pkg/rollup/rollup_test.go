@@ -0,0 +1,128 @@
+package rollup
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/goccha/times/pkg/civil"
+	"github.com/goccha/times/pkg/gauge"
+	"github.com/goccha/times/pkg/weeks"
+)
+
+func shift(loc *time.Location, s string, hours float64) *gauge.TimeGauge {
+	begin, err := time.ParseInLocation("2006-01-02 15:04", s, loc)
+	if err != nil {
+		panic(err)
+	}
+	return gauge.New(begin, begin.Add(time.Duration(hours*float64(time.Hour))))
+}
+
+func bucketsString(buckets []*Bucket) string {
+	s := ""
+	for _, b := range buckets {
+		s += fmt.Sprintf("%s=%v(%d) ", b.Date, b.Total, len(b.Segments))
+	}
+	return s
+}
+
+func TestBuckets_Daily(t *testing.T) {
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	gauges := []*gauge.TimeGauge{
+		shift(tokyo, "2020-04-03 22:00", 7), // 翌日にまたがる
+		shift(tokyo, "2020-04-01 09:00", 8),
+	}
+	buckets := Buckets(gauges, Daily(civil.TimeOfDay{}), tokyo)
+	expected := "2020-04-01=8h0m0s(1) 2020-04-02=0s(0) 2020-04-03=2h0m0s(1) 2020-04-04=5h0m0s(1) "
+	if actual := bucketsString(buckets); actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+	// 5時区切りの場合は夜勤が1日に収まる
+	buckets = Buckets(gauges, Daily(civil.TimeOfDay{Hour: 5}), tokyo)
+	expected = "2020-04-01=8h0m0s(1) 2020-04-02=0s(0) 2020-04-03=7h0m0s(1) "
+	if actual := bucketsString(buckets); actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+	if b := buckets[2].Gauge; b.Begin().Format(time.RFC3339) != "2020-04-03T05:00:00+09:00" || b.Hours() != 24 {
+		t.Errorf("unexpected bucket %s", b.Interval())
+	}
+}
+
+func TestBuckets_Weekly(t *testing.T) {
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	gauges := []*gauge.TimeGauge{
+		shift(tokyo, "2020-04-01 09:00", 8),
+		shift(tokyo, "2020-04-05 09:00", 8),
+		shift(tokyo, "2020-04-20 09:00", 4),
+	}
+	buckets := Buckets(gauges, Weekly(weeks.ISO), tokyo)
+	expected := "2020-03-30=16h0m0s(2) 2020-04-06=0s(0) 2020-04-13=0s(0) 2020-04-20=4h0m0s(1) "
+	if actual := bucketsString(buckets); actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+	buckets = Buckets(gauges, Weekly(weeks.Sunday), tokyo)
+	expected = "2020-03-29=8h0m0s(1) 2020-04-05=8h0m0s(1) 2020-04-12=0s(0) 2020-04-19=4h0m0s(1) "
+	if actual := bucketsString(buckets); actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+}
+
+func TestBuckets_Monthly(t *testing.T) {
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	gauges := []*gauge.TimeGauge{
+		shift(tokyo, "2020-01-31 20:00", 8),
+		shift(tokyo, "2020-03-15 09:00", 8),
+	}
+	buckets := Buckets(gauges, Monthly(), tokyo)
+	expected := "2020-01-01=4h0m0s(1) 2020-02-01=4h0m0s(1) 2020-03-01=8h0m0s(1) "
+	if actual := bucketsString(buckets); actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+	if buckets[1].Segments[0].Begin().Format(time.RFC3339) != "2020-02-01T00:00:00+09:00" {
+		t.Errorf("unexpected segment %s", buckets[1].Segments[0].Interval())
+	}
+}
+
+func TestBuckets_Fiscal(t *testing.T) {
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	gauges := []*gauge.TimeGauge{
+		shift(tokyo, "2020-03-31 09:00", 8),
+		shift(tokyo, "2020-04-01 09:00", 8),
+		shift(tokyo, "2020-10-01 09:00", 8),
+	}
+	buckets := Buckets(gauges, Fiscal(time.April, 12), tokyo)
+	expected := "2019-04-01=8h0m0s(1) 2020-04-01=16h0m0s(2) "
+	if actual := bucketsString(buckets); actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+	buckets = Buckets(gauges, Fiscal(time.April, 3), tokyo)
+	expected = "2020-01-01=8h0m0s(1) 2020-04-01=8h0m0s(1) 2020-07-01=0s(0) 2020-10-01=8h0m0s(1) "
+	if actual := bucketsString(buckets); actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+}
+
+func TestBucketsWithin(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	gauges := []*gauge.TimeGauge{
+		shift(ny, "2020-03-07 22:00", 8), // 夏時間の開始をまたぐ
+	}
+	window := gauge.New(time.Date(2020, 3, 6, 0, 0, 0, 0, ny), time.Date(2020, 3, 10, 0, 0, 0, 0, ny))
+	buckets := BucketsWithin(gauges, Daily(civil.TimeOfDay{}), ny, window)
+	expected := "2020-03-06=0s(0) 2020-03-07=2h0m0s(1) 2020-03-08=6h0m0s(1) 2020-03-09=0s(0) "
+	if actual := bucketsString(buckets); actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+	if buckets[2].Gauge.Hours() != 23 {
+		t.Errorf("expected=23, actual=%v", buckets[2].Gauge.Hours())
+	}
+	// window 外の時間は集計しない
+	window = gauge.New(time.Date(2020, 3, 8, 0, 0, 0, 0, ny), time.Date(2020, 3, 8, 1, 0, 0, 0, ny))
+	buckets = BucketsWithin(gauges, Daily(civil.TimeOfDay{}), ny, window)
+	if len(buckets) != 1 || buckets[0].Total != time.Hour {
+		t.Errorf("unexpected buckets %s", bucketsString(buckets))
+	}
+	if actual := Buckets(nil, Monthly(), ny); len(actual) != 0 {
+		t.Errorf("expected=0, actual=%d", len(actual))
+	}
+}
@@ -0,0 +1,237 @@
+package schedule
+
+import (
+	"sort"
+	"time"
+
+	"github.com/goccha/times/pkg/civil"
+	"github.com/goccha/times/pkg/gauge"
+	"github.com/goccha/times/pkg/zone"
+)
+
+// Hours 勤務時間(終了時刻が開始時刻以前の場合は翌日の時刻とする)
+type Hours struct {
+	Start    civil.TimeOfDay
+	End      civil.TimeOfDay
+	Weekdays []time.Weekday // 対象の曜日(空の場合は全ての曜日)
+}
+
+// Option Finder のオプション
+type Option func(f *Finder)
+
+// WithHours 勤務時間を指定する(複数指定可、指定しない場合は終日を対象とする)
+func WithHours(start, end civil.TimeOfDay, weekdays ...time.Weekday) Option {
+	return func(f *Finder) {
+		f.hours = append(f.hours, Hours{Start: start, End: end, Weekdays: weekdays})
+	}
+}
+
+// WithLocation 勤務時間・開始時刻の揃え方を評価するロケーションを指定する(既定は time.Local)
+func WithLocation(loc *time.Location) Option {
+	return func(f *Finder) {
+		if loc != nil {
+			f.loc = loc
+		}
+	}
+}
+
+// WithAlignment 空き枠の開始時刻を0時からの d 単位(15分、30分など)に揃える
+func WithAlignment(d time.Duration) Option {
+	return func(f *Finder) {
+		f.align = d
+	}
+}
+
+// WithBuffer 予定の前後に確保する時間を指定する(before は空き枠の前、after は空き枠の後)
+func WithBuffer(before, after time.Duration) Option {
+	return func(f *Finder) {
+		f.before = before
+		f.after = after
+	}
+}
+
+// WithLimit 返す空き枠の最大数を指定する(0以下は無制限)
+func WithLimit(n int) Option {
+	return func(f *Finder) {
+		f.limit = n
+	}
+}
+
+// Finder 予定の空き枠を探す
+type Finder struct {
+	hours  []Hours
+	loc    *time.Location
+	align  time.Duration
+	before time.Duration
+	after  time.Duration
+	limit  int
+}
+
+// NewFinder 空き枠を探す Finder を生成する
+func NewFinder(opts ...Option) *Finder {
+	f := &Finder{loc: time.Local}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Free 検索範囲内で全ての参加者の予定が空いている勤務時間を開始日時の順に返す
+//
+// busy には参加者毎の予定を指定する。
+func (f *Finder) Free(window *gauge.TimeGauge, busy ...[]*gauge.TimeGauge) []*gauge.TimeGauge {
+	free := subtract(f.available(window), f.blocked(busy))
+	result := make([]*gauge.TimeGauge, 0, len(free))
+	for _, s := range free {
+		result = append(result, gauge.New(s.begin, s.end))
+	}
+	return result
+}
+
+// Slots 検索範囲内で全ての参加者の予定が空いている length の長さの空き枠を開始日時の順に返す
+//
+// 空き時間の先頭から、WithAlignment を指定した場合はその間隔で、指定しない場合は length の間隔で候補を返す。
+func (f *Finder) Slots(window *gauge.TimeGauge, length time.Duration, busy ...[]*gauge.TimeGauge) []*gauge.TimeGauge {
+	slots := make([]*gauge.TimeGauge, 0)
+	if length <= 0 {
+		return slots
+	}
+	step := length
+	if f.align > 0 {
+		step = f.align
+	}
+	for _, s := range subtract(f.available(window), f.blocked(busy)) {
+		for begin := f.aligned(s.begin); !begin.Add(length).After(s.end); begin = begin.Add(step) {
+			slots = append(slots, gauge.New(begin, begin.Add(length)))
+			if f.limit > 0 && len(slots) >= f.limit {
+				return slots
+			}
+		}
+	}
+	return slots
+}
+
+// aligned 開始時刻を揃えた日時を返す
+func (f *Finder) aligned(t time.Time) time.Time {
+	if f.align <= 0 {
+		return t
+	}
+	wall := zone.Wall(t.In(f.loc))
+	if rem := wall.Sub(wall.Truncate(24*time.Hour)) % f.align; rem != 0 {
+		return t.Add(f.align - rem)
+	}
+	return t
+}
+
+// available 検索範囲内の勤務時間を返す
+func (f *Finder) available(window *gauge.TimeGauge) []span {
+	w := span{window.Begin(), window.End()}
+	if !w.end.After(w.begin) {
+		return nil
+	}
+	if len(f.hours) == 0 {
+		return []span{w}
+	}
+	var spans []span
+	last := civil.DateOf(w.end.In(f.loc))
+	for d := civil.DateOf(w.begin.In(f.loc)).AddDays(-1); !d.After(last); d = d.AddDays(1) {
+		for _, h := range f.hours {
+			if !h.contains(d.Weekday()) {
+				continue
+			}
+			s := span{h.Start.On(d, f.loc), h.End.On(d, f.loc)}
+			if !h.End.After(h.Start) {
+				s.end = h.End.On(d.AddDays(1), f.loc)
+			}
+			if s, ok := s.intersect(w); ok {
+				spans = append(spans, s)
+			}
+		}
+	}
+	return merge(spans)
+}
+
+// blocked 全ての参加者の予定に前後の時間を加えて結合した期間を返す
+func (f *Finder) blocked(busy [][]*gauge.TimeGauge) []span {
+	var spans []span
+	for _, list := range busy {
+		for _, tg := range list {
+			if tg.End().After(tg.Begin()) {
+				spans = append(spans, span{tg.Begin().Add(-f.after), tg.End().Add(f.before)})
+			}
+		}
+	}
+	return merge(spans)
+}
+
+func (h Hours) contains(w time.Weekday) bool {
+	if len(h.Weekdays) == 0 {
+		return true
+	}
+	for _, v := range h.Weekdays {
+		if v == w {
+			return true
+		}
+	}
+	return false
+}
+
+// span 開始日時を含み終了日時を含まない期間
+type span struct {
+	begin time.Time
+	end   time.Time
+}
+
+func (s span) intersect(u span) (span, bool) {
+	if u.begin.After(s.begin) {
+		s.begin = u.begin
+	}
+	if u.end.Before(s.end) {
+		s.end = u.end
+	}
+	return s, s.end.After(s.begin)
+}
+
+// merge 期間を開始日時の順に並べ、重複・隣接する期間を結合する
+func merge(spans []span) []span {
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].begin.Before(spans[j].begin)
+	})
+	result := make([]span, 0, len(spans))
+	for _, s := range spans {
+		if n := len(result); n > 0 && !s.begin.After(result[n-1].end) {
+			if s.end.After(result[n-1].end) {
+				result[n-1].end = s.end
+			}
+			continue
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+// subtract 結合済みの期間から結合済みの期間を除いた期間を返す
+func subtract(spans, blocked []span) []span {
+	var result []span
+	for _, s := range spans {
+		for _, b := range blocked {
+			if !b.end.After(s.begin) {
+				continue
+			}
+			if !b.begin.Before(s.end) {
+				break
+			}
+			if b.begin.After(s.begin) {
+				result = append(result, span{s.begin, b.begin})
+			}
+			s.begin = b.end
+			if !s.end.After(s.begin) {
+				break
+			}
+		}
+		if s.end.After(s.begin) {
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -0,0 +1,72 @@
+package schedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goccha/times/pkg/civil"
+	"github.com/goccha/times/pkg/gauge"
+)
+
+func intervals(gauges []*gauge.TimeGauge) string {
+	s := make([]string, len(gauges))
+	for i, tg := range gauges {
+		s[i] = tg.Begin().Format("01-02 15:04") + "/" + tg.End().Format("15:04")
+	}
+	return strings.Join(s, ",")
+}
+
+func TestFinder(t *testing.T) {
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2020, 4, day, hour, min, 0, 0, tokyo)
+	}
+	window := gauge.New(at(6, 0, 0), at(8, 0, 0)) // 4/6(月) 〜 4/7(火)
+	alice := []*gauge.TimeGauge{
+		gauge.New(at(6, 9, 0), at(6, 10, 0)),
+		gauge.New(at(6, 13, 0), at(6, 14, 30)),
+	}
+	bob := []*gauge.TimeGauge{
+		gauge.New(at(6, 10, 0), at(6, 12, 10)),
+		gauge.New(at(7, 9, 0), at(7, 17, 30)),
+	}
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	hours := WithHours(civil.TimeOfDay{Hour: 9}, civil.TimeOfDay{Hour: 18}, weekdays...)
+
+	f := NewFinder(hours, WithLocation(tokyo), WithAlignment(30*time.Minute))
+	if actual, expected := intervals(f.Free(window, alice, bob)), "04-06 12:10/13:00,04-06 14:30/18:00,04-07 17:30/18:00"; actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+	if actual, expected := intervals(f.Slots(window, time.Hour, alice, bob)), "04-06 14:30/15:30,04-06 15:00/16:00,04-06 15:30/16:30,04-06 16:00/17:00,04-06 16:30/17:30,04-06 17:00/18:00"; actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+
+	f = NewFinder(hours, WithLocation(tokyo), WithAlignment(30*time.Minute), WithBuffer(15*time.Minute, 15*time.Minute), WithLimit(2))
+	if actual, expected := intervals(f.Slots(window, time.Hour, alice, bob)), "04-06 15:00/16:00,04-06 15:30/16:30"; actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+
+	// 開始時刻を揃えない場合は空き時間の先頭から length の間隔
+	f = NewFinder(hours, WithLocation(tokyo))
+	if actual, expected := intervals(f.Slots(window, 40*time.Minute, alice, bob)), "04-06 12:10/12:50,04-06 14:30/15:10,04-06 15:10/15:50,04-06 15:50/16:30,04-06 16:30/17:10,04-06 17:10/17:50"; actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+}
+
+func TestFinder_Overnight(t *testing.T) {
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	window := gauge.New(time.Date(2020, 4, 6, 0, 0, 0, 0, tokyo), time.Date(2020, 4, 7, 0, 0, 0, 0, tokyo))
+	f := NewFinder(WithHours(civil.TimeOfDay{Hour: 22}, civil.TimeOfDay{Hour: 6}), WithLocation(tokyo))
+	busy := []*gauge.TimeGauge{gauge.New(time.Date(2020, 4, 6, 2, 0, 0, 0, tokyo), time.Date(2020, 4, 6, 3, 0, 0, 0, tokyo))}
+	if actual, expected := intervals(f.Free(window, busy)), "04-06 00:00/02:00,04-06 03:00/06:00,04-06 22:00/00:00"; actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+	// 勤務時間を指定しない場合は検索範囲全体
+	if actual, expected := intervals(NewFinder(WithLocation(tokyo)).Free(window, busy)), "04-06 00:00/02:00,04-06 03:00/00:00"; actual != expected {
+		t.Errorf("expected=%s, actual=%s", expected, actual)
+	}
+	if slots := f.Slots(window, 0, busy); len(slots) != 0 {
+		t.Errorf("expected=0, actual=%d", len(slots))
+	}
+}
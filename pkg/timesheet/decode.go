@@ -0,0 +1,195 @@
+package timesheet
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/goccha/times/pkg/civil"
+	"github.com/goccha/times/pkg/gauge"
+)
+
+var bom = []byte{0xEF, 0xBB, 0xBF}
+
+// Decoder CSV 形式の勤怠を読み込む
+type Decoder struct {
+	r     *csv.Reader
+	c     *config
+	index map[string]int
+}
+
+// NewDecoder CSV 形式の勤怠を読み込む Decoder を生成する(先頭の BOM は無視する)
+func NewDecoder(r io.Reader, opts ...Option) *Decoder {
+	br := bufio.NewReader(r)
+	if b, err := br.Peek(len(bom)); err == nil && bytes.Equal(b, bom) {
+		_, _ = br.Discard(len(bom))
+	}
+	c := newConfig(opts)
+	cr := csv.NewReader(br)
+	cr.Comma = c.comma
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	return &Decoder{r: cr, c: c}
+}
+
+// Read 1行を読み込む
+//
+// 行の値が不正な場合は *RowError を返し、続けて次の行を読み込むことができる。
+// 全ての行を読み込んだ場合は io.EOF を返す。
+func (d *Decoder) Read() (*Record, error) {
+	if d.index == nil {
+		if err := d.readHeader(); err != nil {
+			return nil, err
+		}
+	}
+	row, err := d.r.Read()
+	if err != nil {
+		var pe *csv.ParseError
+		if errors.As(err, &pe) {
+			return nil, &RowError{Line: pe.Line, Err: pe.Err}
+		}
+		return nil, err
+	}
+	line, _ := d.r.FieldPos(0)
+	return d.record(line, row)
+}
+
+// ReadAll 全ての行を読み込む
+//
+// 不正な行は読み飛ばし、正しい行と共に不正な行のエラーを RowErrors で返す。
+func (d *Decoder) ReadAll() ([]*Record, error) {
+	var records []*Record
+	var errs RowErrors
+	for {
+		r, err := d.Read()
+		if err == io.EOF {
+			break
+		}
+		var re *RowError
+		if errors.As(err, &re) {
+			errs = append(errs, re)
+			continue
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, r)
+	}
+	if len(errs) > 0 {
+		return records, errs
+	}
+	return records, nil
+}
+
+func (d *Decoder) readHeader() error {
+	header := d.c.header
+	if header == nil {
+		row, err := d.r.Read()
+		if err != nil {
+			if err == io.EOF {
+				return fmt.Errorf("timesheet: missing header")
+			}
+			return err
+		}
+		header = row
+	}
+	d.index = make(map[string]int, len(header))
+	for i, name := range header {
+		d.index[strings.TrimSpace(name)] = i
+	}
+	required := []string{d.c.begin, d.c.end}
+	if d.c.date != "" {
+		required = append(required, d.c.date)
+	}
+	for _, name := range append(required, d.c.labels...) {
+		if _, ok := d.index[name]; !ok {
+			return fmt.Errorf("timesheet: missing column %q", name)
+		}
+	}
+	return nil
+}
+
+func (d *Decoder) value(row []string, name string) string {
+	if i := d.index[name]; i < len(row) {
+		return strings.TrimSpace(row[i])
+	}
+	return ""
+}
+
+func (d *Decoder) record(line int, row []string) (*Record, error) {
+	var begin, end time.Time
+	var err error
+	if d.c.date != "" {
+		if begin, end, err = d.clockRange(line, row); err != nil {
+			return nil, err
+		}
+	} else {
+		if begin, err = d.dateTime(line, row, d.c.begin); err != nil {
+			return nil, err
+		}
+		if end, err = d.dateTime(line, row, d.c.end); err != nil {
+			return nil, err
+		}
+	}
+	tg, err := gauge.NewChecked(begin, end, gauge.AllowZero(), gauge.AllowMixedLocations())
+	if err != nil {
+		return nil, &RowError{Line: line, Column: d.c.end, Err: err}
+	}
+	r := &Record{Line: line, Gauge: tg}
+	if len(d.c.labels) > 0 {
+		r.Labels = make(map[string]string, len(d.c.labels))
+		for _, name := range d.c.labels {
+			r.Labels[name] = d.value(row, name)
+		}
+	}
+	return r, nil
+}
+
+func (d *Decoder) dateTime(line int, row []string, name string) (time.Time, error) {
+	v := d.value(row, name)
+	if v == "" {
+		return time.Time{}, &RowError{Line: line, Column: name, Err: fmt.Errorf("missing value")}
+	}
+	t, err := parseTime(v, d.c.layouts, d.c.loc)
+	if err != nil {
+		return time.Time{}, &RowError{Line: line, Column: name, Err: err}
+	}
+	return t, nil
+}
+
+// clockRange 日付の列と開始・終了時刻の列から期間を解析する
+func (d *Decoder) clockRange(line int, row []string) (time.Time, time.Time, error) {
+	v := d.value(row, d.c.date)
+	if v == "" {
+		return time.Time{}, time.Time{}, &RowError{Line: line, Column: d.c.date, Err: fmt.Errorf("missing value")}
+	}
+	t, err := parseTime(v, d.c.dateLayouts, d.c.loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, &RowError{Line: line, Column: d.c.date, Err: err}
+	}
+	date := civil.DateOf(t)
+	clocks := make([]time.Duration, 2)
+	for i, name := range []string{d.c.begin, d.c.end} {
+		v := d.value(row, name)
+		if v == "" {
+			return time.Time{}, time.Time{}, &RowError{Line: line, Column: name, Err: fmt.Errorf("missing value")}
+		}
+		if clocks[i], err = parseClock(v); err != nil {
+			return time.Time{}, time.Time{}, &RowError{Line: line, Column: name, Err: err}
+		}
+	}
+	if clocks[1] <= clocks[0] {
+		clocks[1] += 24 * time.Hour // 日をまたぐ
+	}
+	return at(date, clocks[0], d.c.loc), at(date, clocks[1], d.c.loc), nil
+}
+
+// at 日付の0時からの壁時計の経過時間の日時を返す
+func at(date civil.Date, clock time.Duration, loc *time.Location) time.Time {
+	return date.At(0, 0, 0, int(clock), loc)
+}
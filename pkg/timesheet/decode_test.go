@@ -0,0 +1,131 @@
+package timesheet
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecoder_ReadAll(t *testing.T) {
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	src := "\xEF\xBB\xBF社員番号,氏名,出勤,退勤\n" +
+		"001,山田,2020/4/1 9:00,2020/4/1 18:00\n" +
+		"002,佐藤,2020年4月1日 22時00分,2020年4月2日 7時00分\n" +
+		"003,鈴木,2020-04-01 09:00,\n" +
+		"004,田中,2020/4/1 18:00,2020/4/1 9:00\n" +
+		"005,高橋,2020-04-01T09:00:00Z,2020-04-01T10:30:00Z\n" +
+		"006,伊藤,yesterday,2020/4/1 9:00\n"
+	d := NewDecoder(strings.NewReader(src),
+		WithColumns("出勤", "退勤"),
+		WithLabels("社員番号", "氏名"),
+		WithLocation(tokyo))
+	records, err := d.ReadAll()
+	if len(records) != 3 {
+		t.Fatalf("expected=3, actual=%d", len(records))
+	}
+	expected := []struct {
+		id    string
+		begin string
+		hours float64
+	}{
+		{"001", "2020-04-01T09:00:00+09:00", 9},
+		{"002", "2020-04-01T22:00:00+09:00", 9},
+		{"005", "2020-04-01T09:00:00Z", 1.5},
+	}
+	for i, r := range records {
+		if r.Labels["社員番号"] != expected[i].id || r.Gauge.Begin().Format(time.RFC3339) != expected[i].begin || r.Gauge.Hours() != expected[i].hours {
+			t.Errorf("[%d] expected=%v, actual=%v %s %v", i, expected[i], r.Labels, r.Gauge.Begin().Format(time.RFC3339), r.Gauge.Hours())
+		}
+	}
+	if records[0].Labels["氏名"] != "山田" || records[0].Line != 2 {
+		t.Errorf("unexpected record %+v", records[0])
+	}
+	var errs RowErrors
+	if !errors.As(err, &errs) || len(errs) != 3 {
+		t.Fatalf("expected 3 row errors, actual=%v", err)
+	}
+	lines := []int{4, 5, 7}
+	columns := []string{"退勤", "退勤", "出勤"}
+	for i, e := range errs {
+		if e.Line != lines[i] || e.Column != columns[i] {
+			t.Errorf("[%d] expected=%d %s, actual=%d %s", i, lines[i], columns[i], e.Line, e.Column)
+		}
+	}
+	if !strings.Contains(err.Error(), "line 4") || !strings.Contains(err.Error(), "2 more errors") {
+		t.Errorf("unexpected error message %s", err)
+	}
+}
+
+func TestDecoder_DateColumn(t *testing.T) {
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	src := "2020/4/1;9:00;18:00\n" +
+		"2020/4/1;22:00;7:00\n" +
+		"2020/4/2;17時30分;26:15\n" +
+		"2020/4/3;9:00;25:61\n"
+	d := NewDecoder(strings.NewReader(src),
+		WithComma(';'),
+		WithoutHeader("date", "begin", "end"),
+		WithDateColumn("date"),
+		WithLocation(tokyo))
+	var actual []string
+	for {
+		r, err := d.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var re *RowError
+			if !errors.As(err, &re) || re.Line != 4 || re.Column != "end" {
+				t.Errorf("unexpected error %v", err)
+			}
+			continue
+		}
+		actual = append(actual, r.Gauge.Interval())
+	}
+	expected := []string{
+		"2020-04-01T09:00:00+09:00/2020-04-01T18:00:00+09:00",
+		"2020-04-01T22:00:00+09:00/2020-04-02T07:00:00+09:00",
+		"2020-04-02T17:30:00+09:00/2020-04-03T02:15:00+09:00",
+	}
+	if strings.Join(actual, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected=%v, actual=%v", expected, actual)
+	}
+}
+
+func TestDecoder_Error(t *testing.T) {
+	if _, err := NewDecoder(strings.NewReader("")).ReadAll(); err == nil {
+		t.Error("expected error")
+	}
+	_, err := NewDecoder(strings.NewReader("start,end\n")).ReadAll()
+	if err == nil || !strings.Contains(err.Error(), `missing column "begin"`) {
+		t.Errorf("unexpected error %v", err)
+	}
+	records, err := NewDecoder(strings.NewReader("begin,end\n\"2020-04-01 09:00,2020-04-01 10:00\n")).ReadAll()
+	if err == nil || len(records) != 0 {
+		t.Errorf("unexpected result %v %v", records, err)
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected time.Duration
+	}{
+		{"9:00", 9 * time.Hour},
+		{"09:05:30", 9*time.Hour + 5*time.Minute + 30*time.Second},
+		{"26:15", 26*time.Hour + 15*time.Minute},
+		{"17時30分", 17*time.Hour + 30*time.Minute},
+	}
+	for _, tt := range tests {
+		if actual, err := parseClock(tt.s); err != nil || actual != tt.expected {
+			t.Errorf("%s: expected=%v, actual=%v %v", tt.s, tt.expected, actual, err)
+		}
+	}
+	for _, s := range []string{"", "9", "9:5", "9:60", "100:00", "a:00", "9:00:00:00"} {
+		if _, err := parseClock(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
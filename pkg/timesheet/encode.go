@@ -0,0 +1,86 @@
+package timesheet
+
+import (
+	"encoding/csv"
+	"io"
+
+	"github.com/goccha/times/pkg/civil"
+	"github.com/goccha/times/pkg/zone"
+)
+
+// Encoder CSV 形式で勤怠を出力する
+type Encoder struct {
+	w      *csv.Writer
+	c      *config
+	header bool
+}
+
+// NewEncoder CSV 形式で勤怠を出力する Encoder を生成する
+func NewEncoder(w io.Writer, opts ...Option) *Encoder {
+	c := newConfig(opts)
+	cw := csv.NewWriter(w)
+	cw.Comma = c.comma
+	return &Encoder{w: cw, c: c}
+}
+
+// columns 出力する列名
+func (e *Encoder) columns() []string {
+	if e.c.header != nil {
+		return e.c.header
+	}
+	var columns []string
+	if e.c.date != "" {
+		columns = append(columns, e.c.date)
+	}
+	columns = append(columns, e.c.begin, e.c.end)
+	return append(columns, e.c.labels...)
+}
+
+// Write 1行を出力する(最初の行の前にヘッダ行を出力する)
+func (e *Encoder) Write(r *Record) error {
+	columns := e.columns()
+	if !e.header {
+		e.header = true
+		if e.c.header == nil {
+			if err := e.w.Write(columns); err != nil {
+				return err
+			}
+		}
+	}
+	values := make(map[string]string, len(columns))
+	for k, v := range r.Labels {
+		values[k] = v
+	}
+	begin, end := r.Gauge.Begin().In(e.c.loc), r.Gauge.End().In(e.c.loc)
+	if e.c.date != "" {
+		date := civil.DateOf(begin)
+		midnight := zone.Wall(date.In(e.c.loc))
+		values[e.c.date] = date.Format(e.c.dateLayouts[0])
+		values[e.c.begin] = formatClock(zone.Wall(begin).Sub(midnight))
+		values[e.c.end] = formatClock(zone.Wall(end).Sub(midnight))
+	} else {
+		values[e.c.begin] = begin.Format(e.c.layouts[0])
+		values[e.c.end] = end.Format(e.c.layouts[0])
+	}
+	row := make([]string, len(columns))
+	for i, name := range columns {
+		row[i] = values[name]
+	}
+	return e.w.Write(row)
+}
+
+// WriteAll 全ての行を出力する
+func (e *Encoder) WriteAll(records []*Record) error {
+	for _, r := range records {
+		if err := e.Write(r); err != nil {
+			return err
+		}
+	}
+	return e.Flush()
+}
+
+// Flush バッファを出力する
+func (e *Encoder) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
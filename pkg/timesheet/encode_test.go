@@ -0,0 +1,64 @@
+package timesheet
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goccha/times/pkg/gauge"
+)
+
+func TestEncoder_WriteAll(t *testing.T) {
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	records := []*Record{
+		{Gauge: gauge.New(time.Date(2020, 4, 1, 9, 0, 0, 0, tokyo), time.Date(2020, 4, 1, 18, 0, 0, 0, tokyo)), Labels: map[string]string{"id": "001"}},
+		{Gauge: gauge.New(time.Date(2020, 4, 1, 13, 0, 0, 0, time.UTC), time.Date(2020, 4, 1, 22, 0, 0, 0, time.UTC)), Labels: map[string]string{"id": "002, night"}},
+	}
+	var sb strings.Builder
+	if err := NewEncoder(&sb, WithLabels("id"), WithLocation(tokyo), WithLayouts("2006/1/2 15:04")).WriteAll(records); err != nil {
+		t.Fatal(err)
+	}
+	expected := "begin,end,id\n" +
+		"2020/4/1 09:00,2020/4/1 18:00,001\n" +
+		"2020/4/1 22:00,2020/4/2 07:00,\"002, night\"\n"
+	if sb.String() != expected {
+		t.Errorf("expected=%q, actual=%q", expected, sb.String())
+	}
+
+	sb.Reset()
+	if err := NewEncoder(&sb, WithDateColumn("日付"), WithColumns("出勤", "退勤"), WithLocation(tokyo), WithDateLayouts("2006年1月2日")).WriteAll(records); err != nil {
+		t.Fatal(err)
+	}
+	expected = "日付,出勤,退勤\n" +
+		"2020年4月1日,9:00,18:00\n" +
+		"2020年4月1日,22:00,31:00\n"
+	if sb.String() != expected {
+		t.Errorf("expected=%q, actual=%q", expected, sb.String())
+	}
+
+	// 出力した CSV を読み込む
+	decoded, err := NewDecoder(strings.NewReader(sb.String()), WithDateColumn("日付"), WithColumns("出勤", "退勤"), WithLocation(tokyo)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range decoded {
+		if !r.Gauge.Begin().Equal(records[i].Gauge.Begin()) || !r.Gauge.End().Equal(records[i].Gauge.End()) {
+			t.Errorf("[%d] expected=%s, actual=%s", i, records[i].Gauge.Interval(), r.Gauge.Interval())
+		}
+	}
+}
+
+func TestEncoder_WithoutHeader(t *testing.T) {
+	var sb strings.Builder
+	e := NewEncoder(&sb, WithoutHeader("end", "begin"), WithLocation(time.UTC), WithComma('\t'))
+	begin := time.Date(2020, 4, 1, 9, 0, 0, 0, time.UTC)
+	if err := e.Write(&Record{Gauge: gauge.New(begin, begin.Add(time.Hour))}); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "2020-04-01 10:00:00\t2020-04-01 09:00:00\n"; sb.String() != expected {
+		t.Errorf("expected=%q, actual=%q", expected, sb.String())
+	}
+}
@@ -0,0 +1,214 @@
+package timesheet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccha/times/pkg/gauge"
+)
+
+// DefaultLayouts 既定の日時の書式(先頭の書式で出力する)
+var DefaultLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006/1/2 15:04:05",
+	"2006/1/2 15:04",
+	"2006年1月2日 15時04分05秒",
+	"2006年1月2日 15時04分",
+	"2006年1月2日 15:04",
+	time.RFC3339Nano,
+}
+
+// DefaultDateLayouts 既定の日付の書式(先頭の書式で出力する)
+var DefaultDateLayouts = []string{
+	"2006-01-02",
+	"2006/1/2",
+	"2006年1月2日",
+}
+
+// Record 勤怠の1行
+type Record struct {
+	Line   int               // 行番号(読み込み時のみ)
+	Gauge  *gauge.TimeGauge  // 勤務期間
+	Labels map[string]string // ラベルの列の値
+}
+
+// Option CSV の形式を指定する
+type Option func(c *config)
+
+type config struct {
+	begin       string
+	end         string
+	date        string
+	labels      []string
+	layouts     []string
+	dateLayouts []string
+	loc         *time.Location
+	comma       rune
+	header      []string
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{
+		begin:       "begin",
+		end:         "end",
+		layouts:     DefaultLayouts,
+		dateLayouts: DefaultDateLayouts,
+		loc:         time.Local,
+		comma:       ',',
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithColumns 開始日時・終了日時の列名を指定する(既定は begin, end)
+func WithColumns(begin, end string) Option {
+	return func(c *config) {
+		c.begin = begin
+		c.end = end
+	}
+}
+
+// WithDateColumn 日付の列名を指定する
+//
+// 日付の列を指定した場合は、開始・終了の列を時刻("9:00", "26:00", "17時30分")として解釈し、
+// 終了時刻が開始時刻以前の場合は翌日の時刻とする。
+func WithDateColumn(name string) Option {
+	return func(c *config) {
+		c.date = name
+	}
+}
+
+// WithLabels ラベルとして読み書きする列名を指定する
+func WithLabels(names ...string) Option {
+	return func(c *config) {
+		c.labels = names
+	}
+}
+
+// WithLayouts 日時の書式を指定する(読み込み時は順に試し、出力時は先頭の書式を使用する)
+func WithLayouts(layouts ...string) Option {
+	return func(c *config) {
+		if len(layouts) > 0 {
+			c.layouts = layouts
+		}
+	}
+}
+
+// WithDateLayouts 日付の列の書式を指定する(読み込み時は順に試し、出力時は先頭の書式を使用する)
+func WithDateLayouts(layouts ...string) Option {
+	return func(c *config) {
+		if len(layouts) > 0 {
+			c.dateLayouts = layouts
+		}
+	}
+}
+
+// WithLocation タイムゾーンの指定が無い日時を解釈・出力するロケーションを指定する(既定は time.Local)
+func WithLocation(loc *time.Location) Option {
+	return func(c *config) {
+		if loc != nil {
+			c.loc = loc
+		}
+	}
+}
+
+// WithComma 区切り文字を指定する(既定はカンマ)
+func WithComma(r rune) Option {
+	return func(c *config) {
+		c.comma = r
+	}
+}
+
+// WithoutHeader ヘッダ行が無いことを指定し、列名を列の順に指定する
+func WithoutHeader(names ...string) Option {
+	return func(c *config) {
+		c.header = names
+	}
+}
+
+// RowError 行の解析エラー
+type RowError struct {
+	Line   int    // 行番号
+	Column string // 列名(行全体のエラーの場合は空)
+	Err    error
+}
+
+// Error implements the error interface.
+func (e *RowError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("timesheet: line %d: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("timesheet: line %d, column %q: %v", e.Line, e.Column, e.Err)
+}
+
+// Unwrap 元のエラーを返す
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// RowErrors 複数の行の解析エラー
+type RowErrors []*RowError
+
+// Error implements the error interface.
+func (e RowErrors) Error() string {
+	switch len(e) {
+	case 0:
+		return "timesheet: no errors"
+	case 1:
+		return e[0].Error()
+	}
+	return e[0].Error() + " (and " + strconv.Itoa(len(e)-1) + " more errors)"
+}
+
+// Unwrap 各行のエラーを返す
+func (e RowErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, v := range e {
+		errs[i] = v
+	}
+	return errs
+}
+
+// parseTime 日時を書式を順に試して解析する
+func parseTime(s string, layouts []string, loc *time.Location) (time.Time, error) {
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date-time %q", s)
+}
+
+// parseClock "9:00", "26:30", "9:00:15", "17時30分" 形式の時刻を0時からの経過時間として解析する(24時以降も可)
+func parseClock(s string) (time.Duration, error) {
+	v := strings.NewReplacer("時", ":", "分", ":", "秒", "").Replace(s)
+	v = strings.TrimSuffix(v, ":")
+	parts := strings.Split(v, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	units := []time.Duration{time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 || i > 0 && (n >= 60 || len(p) != 2) || i == 0 && len(p) > 2 {
+			return 0, fmt.Errorf("invalid time %q", s)
+		}
+		d += time.Duration(n) * units[i]
+	}
+	return d, nil
+}
+
+// formatClock 0時からの経過時間を "15:04" 形式(秒がある場合は "15:04:05")で返す
+func formatClock(d time.Duration) string {
+	h, m, s := int(d/time.Hour), int(d%time.Hour/time.Minute), int(d%time.Minute/time.Second)
+	if s != 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%d:%02d", h, m)
+}
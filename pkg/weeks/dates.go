@@ -0,0 +1,30 @@
+package weeks
+
+import (
+	"github.com/goccha/times/pkg/civil"
+)
+
+// Dates 引数の日付が含まれる週の日曜日から土曜日までの日付を返す
+func Dates(d civil.Date) []civil.Date {
+	return Sunday.Dates(d)
+}
+
+// ISODates 引数の日付が含まれる週の月曜日から日曜日までの日付を返す
+func ISODates(d civil.Date) []civil.Date {
+	return ISO.Dates(d)
+}
+
+// StartDate 引数の日付が含まれる週の開始日を返す
+func (r Rule) StartDate(d civil.Date) civil.Date {
+	return d.AddDays(-((int(d.Weekday()) - int(r) + 7) % 7))
+}
+
+// Dates 引数の日付が含まれる週の開始日から7日分の日付を返す
+func (r Rule) Dates(d civil.Date) []civil.Date {
+	start := r.StartDate(d)
+	week := make([]civil.Date, 0, 7)
+	for i := 0; i < 7; i++ {
+		week = append(week, start.AddDays(i))
+	}
+	return week
+}
@@ -0,0 +1,42 @@
+package weeks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goccha/times/pkg/civil"
+)
+
+func TestDates(t *testing.T) {
+	d := civil.Date{Year: 2020, Month: time.April, Day: 1}
+	expected := []string{"2020-03-29", "2020-03-30", "2020-03-31", "2020-04-01", "2020-04-02", "2020-04-03", "2020-04-04"}
+	for i, v := range Dates(d) {
+		if v.String() != expected[i] {
+			t.Errorf("[%d] expected=%s, actual=%s", i, expected[i], v)
+		}
+	}
+}
+
+func TestISODates(t *testing.T) {
+	d := civil.Date{Year: 2020, Month: time.April, Day: 5}
+	expected := []string{"2020-03-30", "2020-03-31", "2020-04-01", "2020-04-02", "2020-04-03", "2020-04-04", "2020-04-05"}
+	for i, v := range ISODates(d) {
+		if v.String() != expected[i] {
+			t.Errorf("[%d] expected=%s, actual=%s", i, expected[i], v)
+		}
+	}
+}
+
+func TestRule_Dates(t *testing.T) {
+	d := civil.Date{Year: 2020, Month: time.January, Day: 1}
+	week := Rule(time.Saturday).Dates(d)
+	if week[0].String() != "2019-12-28" || week[6].String() != "2020-01-03" {
+		t.Errorf("expected=2019-12-28..2020-01-03, actual=%s..%s", week[0], week[6])
+	}
+	times := Rule(time.Saturday).Times(d.In(time.UTC))
+	for i, v := range week {
+		if v != civil.DateOf(times[i]) {
+			t.Errorf("[%d] expected=%s, actual=%s", i, civil.DateOf(times[i]), v)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package weeks
+
+import (
+	"time"
+)
+
+// Rule 週の開始曜日
+type Rule time.Weekday
+
+const (
+	// Sunday 日曜開始
+	Sunday = Rule(time.Sunday)
+	// ISO 月曜開始(ISO 8601)
+	ISO = Rule(time.Monday)
+)
+
+// Weekday 週の開始曜日を返す
+func (r Rule) Weekday() time.Weekday {
+	return time.Weekday(r)
+}
+
+// Start 引数の日付が含まれる週の開始日を返す(時刻は引数の日付のまま)
+func (r Rule) Start(t time.Time) time.Time {
+	diff := (int(t.Weekday()) - int(r) + 7) % 7
+	if diff == 0 {
+		return t
+	}
+	return t.AddDate(0, 0, -diff)
+}
+
+// Times 引数の日付が含まれる週の開始日から7日分の日付を返す
+func (r Rule) Times(t time.Time) []time.Time {
+	start := r.Start(t)
+	week := make([]time.Time, 0, 7)
+	week = append(week, start)
+	for i := 1; i < 7; i++ {
+		week = append(week, start.AddDate(0, 0, i))
+	}
+	return week
+}
+
+// Same 引数の日付が同じ週に含まれるかを返す
+func (r Rule) Same(t1 time.Time, t2 time.Time) bool {
+	s1, s2 := r.Start(t1), r.Start(t2.In(t1.Location()))
+	return s1.Year() == s2.Year() && s1.YearDay() == s2.YearDay()
+}
+
+// Week 引数の日付が含まれる週の年と週番号を返す
+//
+// 第1週はその年の日付を4日以上含む最初の週とする(開始曜日が月曜の場合は time.Time.ISOWeek と同じ)。
+func (r Rule) Week(t time.Time) (year, week int) {
+	// 週の4日目が含まれる年をその週の年とする
+	d := r.Start(t).AddDate(0, 0, 3)
+	return d.Year(), (d.YearDay()-1)/7 + 1
+}
+
+// WeeksInYear 指定した年の週数(52 または 53)を返す
+func (r Rule) WeeksInYear(year int) int {
+	// 12月28日は必ずその年の最終週に含まれる
+	_, w := r.Week(time.Date(year, time.December, 28, 0, 0, 0, 0, time.UTC))
+	return w
+}
@@ -0,0 +1,77 @@
+package weeks
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRule_Start(t *testing.T) {
+	tm, _ := time.Parse(time.RFC3339, "2020-04-01T10:00:00+09:00")
+	tests := map[Rule]string{
+		Sunday:               "2020-03-29T10:00:00+09:00",
+		ISO:                  "2020-03-30T10:00:00+09:00",
+		Rule(time.Wednesday): "2020-04-01T10:00:00+09:00",
+		Rule(time.Thursday):  "2020-03-26T10:00:00+09:00",
+		Rule(time.Saturday):  "2020-03-28T10:00:00+09:00",
+	}
+	for r, expected := range tests {
+		actual := r.Start(tm).Format(time.RFC3339)
+		if actual != expected {
+			t.Errorf("[%v] expected=%s, actual=%s", r.Weekday(), expected, actual)
+		}
+	}
+}
+
+func TestRule_Times(t *testing.T) {
+	tm, _ := time.Parse(time.RFC3339, "2020-04-01T10:00:00+09:00")
+	for _, r := range []Rule{Sunday, ISO} {
+		var expected []time.Time
+		if r == Sunday {
+			expected = Times(tm)
+		} else {
+			expected = ISOTimes(tm)
+		}
+		actual := r.Times(tm)
+		for i := range expected {
+			if !actual[i].Equal(expected[i]) {
+				t.Errorf("[%v][%d] expected=%v, actual=%v", r.Weekday(), i, expected[i], actual[i])
+			}
+		}
+	}
+}
+
+func TestRule_Same(t *testing.T) {
+	tm1, _ := time.Parse(time.RFC3339, "2020-03-29T10:00:00+09:00")
+	tm2, _ := time.Parse(time.RFC3339, "2020-04-04T10:00:00+09:00")
+	if !Sunday.Same(tm1, tm2) {
+		t.Error("expected=true, actual=false")
+	}
+	if ISO.Same(tm1, tm2) {
+		t.Error("expected=false, actual=true")
+	}
+	tm1, _ = time.Parse(time.RFC3339, "2020-01-01T10:00:00+09:00")
+	tm2, _ = time.Parse(time.RFC3339, "2019-12-30T10:00:00+09:00")
+	if !ISO.Same(tm1, tm2) {
+		t.Error("expected=true, actual=false")
+	}
+}
+
+func TestRule_Week(t *testing.T) {
+	for d := time.Date(2018, 12, 20, 0, 0, 0, 0, time.UTC); d.Year() < 2022; d = d.AddDate(0, 0, 1) {
+		y1, w1 := d.ISOWeek()
+		y2, w2 := ISO.Week(d)
+		if y1 != y2 || w1 != w2 {
+			t.Fatalf("%s expected=%d-%d, actual=%d-%d", d.Format(time.DateOnly), y1, w1, y2, w2)
+		}
+	}
+	tm, _ := time.Parse(time.RFC3339, "2021-01-03T10:00:00+09:00")
+	if y, w := Sunday.Week(tm); y != 2021 || w != 1 {
+		t.Errorf("expected=2021-1, actual=%d-%d", y, w)
+	}
+	if w := ISO.WeeksInYear(2020); w != 53 {
+		t.Errorf("expected=53, actual=%d", w)
+	}
+	if w := ISO.WeeksInYear(2021); w != 52 {
+		t.Errorf("expected=52, actual=%d", w)
+	}
+}
@@ -0,0 +1,34 @@
+package weeks
+
+import (
+	"iter"
+
+	"github.com/goccha/times/pkg/civil"
+)
+
+// Span 週の開始日から終了日まで(終了日を含む)の範囲
+type Span struct {
+	Begin civil.Date
+	End   civil.Date
+}
+
+// Contains 指定した日付が週に含まれるかどうか
+func (s Span) Contains(d civil.Date) bool {
+	return !d.Before(s.Begin) && !d.After(s.End)
+}
+
+// Between from が含まれる週から to が含まれる週までの週を順に返す
+func Between(from, to civil.Date, rule Rule) iter.Seq[Span] {
+	return func(yield func(Span) bool) {
+		EachBetween(from, to, rule, yield)
+	}
+}
+
+// EachBetween from が含まれる週から to が含まれる週までの週を順に f に渡す(f が false を返した場合は終了する)
+func EachBetween(from, to civil.Date, rule Rule, f func(s Span) bool) {
+	for begin := rule.StartDate(from); !begin.After(to); begin = begin.AddDays(7) {
+		if !f(Span{Begin: begin, End: begin.AddDays(6)}) {
+			return
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package weeks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goccha/times/pkg/civil"
+)
+
+func TestBetween(t *testing.T) {
+	from := civil.Date{Year: 2020, Month: time.April, Day: 1}
+	to := civil.Date{Year: 2020, Month: time.April, Day: 13}
+	var actual []Span
+	for s := range Between(from, to, ISO) {
+		actual = append(actual, s)
+	}
+	expected := []string{"2020-03-30", "2020-04-06", "2020-04-13"}
+	if len(actual) != len(expected) {
+		t.Fatalf("expected=%v, actual=%v", expected, actual)
+	}
+	for i, s := range actual {
+		if s.Begin.String() != expected[i] || s.End != s.Begin.AddDays(6) {
+			t.Errorf("[%d] expected=%s, actual=%v", i, expected[i], s)
+		}
+	}
+	if !actual[0].Contains(from) || actual[1].Contains(from) {
+		t.Error("unexpected Contains")
+	}
+	count := 0
+	EachBetween(from, to, Sunday, func(s Span) bool {
+		count++
+		return s.Begin.Weekday() == time.Sunday
+	})
+	if count != 3 {
+		t.Errorf("expected=3, actual=%d", count)
+	}
+	for range Between(to, from, ISO) {
+		t.Error("expected no span")
+	}
+}
@@ -0,0 +1,97 @@
+package zone
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrNonexistent 夏時間の切り替えで存在しない壁時計の時刻
+	ErrNonexistent = errors.New("zone: nonexistent local time")
+	// ErrAmbiguous 夏時間の切り替えで重複する壁時計の時刻
+	ErrAmbiguous = errors.New("zone: ambiguous local time")
+)
+
+// Policy 夏時間の切り替えで存在しない・重複する壁時計の時刻の解釈方法
+type Policy int
+
+const (
+	// Compatible 存在しない時刻は切り替え幅だけ後にずらし、重複する時刻は早い方とする(RFC 5545)
+	Compatible Policy = iota
+	// Earlier 存在しない時刻は切り替え幅だけ前にずらし、重複する時刻は早い方とする
+	Earlier
+	// Later 存在しない時刻は切り替え幅だけ後にずらし、重複する時刻は遅い方とする
+	Later
+	// Reject 存在しない・重複する時刻をエラーとする
+	Reject
+)
+
+var policyNames = []string{"Compatible", "Earlier", "Later", "Reject"}
+
+// String ポリシーの名前を返す
+func (p Policy) String() string {
+	if p < Compatible || p > Reject {
+		return "Policy(" + strconv.Itoa(int(p)) + ")"
+	}
+	return policyNames[p]
+}
+
+// Date 壁時計の日時を指定したロケーションの日時に変換する
+//
+// time.Date と同様に範囲外の値は正規化する。夏時間の切り替えで存在しない・重複する時刻は policy に従って解釈し、
+// エラーを返すのは policy が Reject の場合のみ。
+func Date(year int, month time.Month, day, hour, min, sec, nsec int, loc *time.Location, policy Policy) (time.Time, error) {
+	return Resolve(time.Date(year, month, day, hour, min, sec, nsec, time.UTC), loc, policy)
+}
+
+// Resolve UTC で表現した壁時計の日時を指定したロケーションの日時に変換する
+func Resolve(wall time.Time, loc *time.Location, policy Policy) (time.Time, error) {
+	if loc == time.UTC {
+		return wall, nil
+	}
+	// 前後1日のオフセットで解釈した候補(切り替えが無い場合は同じ日時)
+	_, before := wall.Add(-24 * time.Hour).In(loc).Zone()
+	_, after := wall.Add(24 * time.Hour).In(loc).Zone()
+	c1 := wall.Add(-time.Duration(before) * time.Second).In(loc)
+	c2 := wall.Add(-time.Duration(after) * time.Second).In(loc)
+	v1, v2 := sameWall(c1, wall), sameWall(c2, wall)
+	switch {
+	case v1 && v2 && !c1.Equal(c2):
+		earlier, later := c1, c2
+		if later.Before(earlier) {
+			earlier, later = later, earlier
+		}
+		switch policy {
+		case Later:
+			return later, nil
+		case Reject:
+			return time.Time{}, ErrAmbiguous
+		}
+		return earlier, nil
+	case v1:
+		return c1, nil
+	case v2:
+		return c2, nil
+	}
+	// 存在しない時刻は切り替え前のオフセットで解釈すると後に、切り替え後のオフセットで解釈すると前にずれる
+	switch policy {
+	case Earlier:
+		return c2, nil
+	case Reject:
+		return time.Time{}, ErrNonexistent
+	}
+	return c1, nil
+}
+
+// Wall 日時の壁時計の時刻を UTC で表現する
+func Wall(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+func sameWall(t, wall time.Time) bool {
+	y, m, d := t.Date()
+	wy, wm, wd := wall.Date()
+	return y == wy && m == wm && d == wd &&
+		t.Hour() == wall.Hour() && t.Minute() == wall.Minute() && t.Second() == wall.Second()
+}
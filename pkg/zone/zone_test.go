@@ -0,0 +1,58 @@
+package zone
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDate(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	london, _ := time.LoadLocation("Europe/London")
+	tests := []struct {
+		loc      *time.Location
+		month    time.Month
+		day      int
+		hour     int
+		policy   Policy
+		expected string
+		err      error
+	}{
+		// 存在しない時刻
+		{ny, time.March, 8, 2, Compatible, "2020-03-08T03:30:00-04:00", nil},
+		{ny, time.March, 8, 2, Later, "2020-03-08T03:30:00-04:00", nil},
+		{ny, time.March, 8, 2, Earlier, "2020-03-08T01:30:00-05:00", nil},
+		{ny, time.March, 8, 2, Reject, "", ErrNonexistent},
+		{london, time.March, 29, 1, Compatible, "2020-03-29T02:30:00+01:00", nil},
+		{london, time.March, 29, 1, Earlier, "2020-03-29T00:30:00Z", nil},
+		// 重複する時刻
+		{ny, time.November, 1, 1, Compatible, "2020-11-01T01:30:00-04:00", nil},
+		{ny, time.November, 1, 1, Earlier, "2020-11-01T01:30:00-04:00", nil},
+		{ny, time.November, 1, 1, Later, "2020-11-01T01:30:00-05:00", nil},
+		{ny, time.November, 1, 1, Reject, "", ErrAmbiguous},
+		{london, time.October, 25, 1, Compatible, "2020-10-25T01:30:00+01:00", nil},
+		{london, time.October, 25, 1, Later, "2020-10-25T01:30:00Z", nil},
+		// 切り替えの無い時刻
+		{ny, time.July, 1, 9, Reject, "2020-07-01T09:30:00-04:00", nil},
+		{time.UTC, time.March, 8, 2, Reject, "2020-03-08T02:30:00Z", nil},
+	}
+	for _, tt := range tests {
+		actual, err := Date(2020, tt.month, tt.day, tt.hour, 30, 0, 0, tt.loc, tt.policy)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s %v: expected=%v, actual=%v", tt.loc, tt.policy, tt.err, err)
+			continue
+		}
+		if err == nil && actual.Format(time.RFC3339) != tt.expected {
+			t.Errorf("%s %v: expected=%s, actual=%s", tt.loc, tt.policy, tt.expected, actual.Format(time.RFC3339))
+		}
+	}
+}
+
+func TestPolicy_String(t *testing.T) {
+	if Later.String() != "Later" {
+		t.Errorf("expected=Later, actual=%s", Later)
+	}
+	if Policy(9).String() != "Policy(9)" {
+		t.Errorf("expected=Policy(9), actual=%s", Policy(9))
+	}
+}